@@ -0,0 +1,137 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	requestIDHeader    = "X-Request-ID"
+	requestIDField     = "request_id"
+	requestIDBytes     = 16
+	maxRequestIDLength = 128
+)
+
+type requestIDContextKey struct{}
+
+// accessLogWriter keeps track of the status and the
+// number of bytes written for the access log
+type accessLogWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+func (w *accessLogWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *accessLogWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += n
+
+	return n, err //nolint:wrapcheck
+}
+
+// Flush keeps streaming responses working through the wrapper
+func (w *accessLogWriter) Flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController get to the rest
+// of the optional interfaces of the wrapped writer
+func (w *accessLogWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// NewAccessLogMiddleware returns a middleware logging every request
+// through the given logger, or the standard one when it's nil, at
+// error level for 5xx responses, warn for 4xx and info for the rest.
+//
+// The request ID comes from the X-Request-ID header or gets generated
+// and is sent back in the response. The handlers get an entry with the
+// request_id field in the request context, see FromContext.
+func NewAccessLogMiddleware(logger *logrus.Logger) func(http.Handler) http.Handler {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			requestID := r.Header.Get(requestIDHeader)
+			if requestID == "" || len(requestID) > maxRequestIDLength {
+				requestID = newRequestID()
+				r.Header.Set(requestIDHeader, requestID)
+			}
+
+			w.Header().Set(requestIDHeader, requestID)
+
+			entry := logger.WithField(requestIDField, requestID)
+
+			ctx := context.WithValue(r.Context(), requestIDContextKey{}, requestID)
+			ctx = WithContext(ctx, entry)
+
+			lw := &accessLogWriter{ResponseWriter: w}
+			next.ServeHTTP(lw, r.WithContext(ctx))
+
+			if lw.status == 0 {
+				lw.status = http.StatusOK
+			}
+
+			entry.WithContext(ctx).WithFields(logrus.Fields{
+				"method":      r.Method,
+				"path":        r.URL.Path,
+				"status":      lw.status,
+				"bytes":       lw.bytes,
+				"duration_ms": float64(time.Since(start)) / float64(time.Millisecond),
+				"remote_addr": r.RemoteAddr,
+				"user_agent":  r.UserAgent(),
+			}).Log(getStatusLevel(lw.status), "http request")
+		})
+	}
+}
+
+// RequestIDFromContext returns the request ID the access
+// log middleware put in the context, if it's there
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+
+	return requestID
+}
+
+func getStatusLevel(status int) logrus.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logrus.ErrorLevel
+	case status >= http.StatusBadRequest:
+		return logrus.WarnLevel
+	default:
+		return logrus.InfoLevel
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, requestIDBytes)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
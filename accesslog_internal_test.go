@@ -0,0 +1,118 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccessLogMiddleware(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(WithFormat("json"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		status        int
+		body          string
+		requestID     string
+		expectedLevel string
+	}{
+		{name: "OK", body: "hello", expectedLevel: "info"},
+		{name: "Client error", status: http.StatusNotFound, expectedLevel: "warning"},
+		{name: "Server error", status: http.StatusBadGateway, body: "oops", expectedLevel: "error"},
+		{name: "Propagated request ID", requestID: "abc-123", expectedLevel: "info"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+
+			var handlerRequestID string
+
+			h := NewAccessLogMiddleware(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				handlerRequestID = RequestIDFromContext(r.Context())
+				assert.Equal(t, handlerRequestID, FromContext(r.Context()).Data[requestIDField])
+
+				if tc.status != 0 {
+					w.WriteHeader(tc.status)
+				}
+
+				_, _ = w.Write([]byte(tc.body))
+			}))
+
+			req := httptest.NewRequest(http.MethodPost, "/orders?id=1", nil)
+			req.Header.Set("User-Agent", "tester")
+
+			if tc.requestID != "" {
+				req.Header.Set(requestIDHeader, tc.requestID)
+			}
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			requestID := rec.Header().Get(requestIDHeader)
+			require.NotEmpty(t, requestID)
+			assert.Equal(t, requestID, handlerRequestID)
+
+			if tc.requestID != "" {
+				assert.Equal(t, tc.requestID, requestID)
+			} else {
+				assert.Len(t, requestID, 2*requestIDBytes)
+			}
+
+			result := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+
+			status := tc.status
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			assert.Equal(t, tc.expectedLevel, result["level"])
+			assert.Equal(t, "http request", result["msg"])
+			assert.Equal(t, http.MethodPost, result["method"])
+			assert.Equal(t, "/orders", result["path"])
+			assert.InDelta(t, status, result["status"], 0)
+			assert.InDelta(t, len(tc.body), result["bytes"], 0)
+			assert.Equal(t, "192.0.2.1:1234", result["remote_addr"])
+			assert.Equal(t, "tester", result["user_agent"])
+			assert.Equal(t, requestID, result[requestIDField])
+			assert.Contains(t, result, "duration_ms")
+		})
+	}
+}
+
+func TestAccessLogWriterFlush(t *testing.T) {
+	rec := httptest.NewRecorder()
+	w := &accessLogWriter{ResponseWriter: rec}
+
+	w.Flush()
+	assert.True(t, rec.Flushed)
+	assert.Equal(t, http.StatusOK, w.status)
+	assert.Equal(t, rec, w.Unwrap())
+}
+
+func TestGetStatusLevel(t *testing.T) {
+	testCases := map[int]logrus.Level{
+		http.StatusOK:                  logrus.InfoLevel,
+		http.StatusMovedPermanently:    logrus.InfoLevel,
+		http.StatusBadRequest:          logrus.WarnLevel,
+		http.StatusTooManyRequests:     logrus.WarnLevel,
+		http.StatusInternalServerError: logrus.ErrorLevel,
+		http.StatusServiceUnavailable:  logrus.ErrorLevel,
+	}
+
+	for status, expected := range testCases {
+		assert.Equal(t, expected, getStatusLevel(status), status)
+	}
+}
@@ -0,0 +1,218 @@
+package logrusconfigurator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// AdminHandler is an http.Handler for looking at and changing the
+// level, format and caller reporting of a logger at runtime.
+//
+// GET returns the current state as JSON. PUT and POST take the same
+// JSON with any of the fields set and an optional ttl, e.g.
+// {"level":"debug","ttl":"15m"}, after which the level goes back
+// to what it was before.
+type AdminHandler struct {
+	logger      *logrus.Logger
+	mu          sync.Mutex
+	revertTimer *time.Timer
+}
+
+type adminState struct {
+	Level        string `json:"level"`
+	Format       string `json:"format"`
+	ReportCaller bool   `json:"reportCaller"`
+}
+
+type adminRequest struct {
+	Level        *string `json:"level"`
+	Format       *string `json:"format"`
+	ReportCaller *bool   `json:"reportCaller"`
+	TTL          string  `json:"ttl"`
+}
+
+type adminError struct {
+	Error string `json:"error"`
+}
+
+// NewAdminHandler returns an AdminHandler for the
+// given logger or for the standard one when it's nil
+func NewAdminHandler(logger *logrus.Logger) *AdminHandler {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	return &AdminHandler{logger: logger}
+}
+
+func (h *AdminHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.mu.Lock()
+		state := h.state()
+		h.mu.Unlock()
+
+		writeAdminJSON(w, http.StatusOK, state)
+	case http.MethodPut, http.MethodPost:
+		state, err := h.update(r)
+		if err != nil {
+			writeAdminJSON(w, http.StatusBadRequest, adminError{Error: err.Error()})
+
+			return
+		}
+
+		writeAdminJSON(w, http.StatusOK, state)
+	default:
+		w.Header().Set("Allow", strings.Join([]string{http.MethodGet, http.MethodPut, http.MethodPost}, ", "))
+		writeAdminJSON(w, http.StatusMethodNotAllowed, adminError{Error: "method not allowed"})
+	}
+}
+
+func (h *AdminHandler) state() adminState {
+	cfg, _ := getLoggerConfig(h.logger)
+
+	return adminState{
+		Level:        getLoggerBaseLevel(h.logger).String(),
+		Format:       cfg.Format,
+		ReportCaller: h.logger.ReportCaller,
+	}
+}
+
+// update validates the whole request before changing anything
+// so a bad field doesn't leave the logger half updated
+func (h *AdminHandler) update(r *http.Request) (adminState, error) {
+	req := adminRequest{}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		return adminState{}, errors.Wrap(err, "invalid request body")
+	}
+
+	var (
+		logrusLevel logrus.Level
+		formatter   logrus.Formatter
+		ttl         time.Duration
+		err         error
+	)
+
+	cfg, _ := getLoggerConfig(h.logger)
+
+	if req.Level != nil {
+		if logrusLevel, err = getLogrusLevel(level(*req.Level)); err != nil {
+			return adminState{}, err
+		}
+	}
+
+	if req.Format != nil {
+		if formatter, err = getLogrusFormat(format(*req.Format)); err != nil {
+			return adminState{}, err
+		}
+
+		colorEnabled, err := isColorEnabled(color(cfg.Color))
+		if err != nil {
+			return adminState{}, err
+		}
+
+		setFormatterColor(formatter, colorEnabled)
+	}
+
+	if req.TTL != "" {
+		if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl <= 0 {
+			return adminState{}, errors.Wrap(errInvalidTTL, req.TTL)
+		}
+
+		if req.Level == nil {
+			return adminState{}, errors.Wrap(errInvalidTTL, "ttl needs a level to revert")
+		}
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+
+	// re-read as the config might have changed in the meantime
+	cfg, _ = getLoggerConfig(h.logger)
+
+	if req.Level != nil {
+		h.setLevel(logrusLevel, ttl)
+		cfg.Level = *req.Level
+	}
+
+	if req.Format != nil {
+		h.logger.SetFormatter(formatter)
+		cfg.Format = *req.Format
+	}
+
+	if req.ReportCaller != nil {
+		h.logger.SetReportCaller(*req.ReportCaller)
+		cfg.ReportCaller = *req.ReportCaller
+	}
+
+	// the output hooks switch over at once without reading the logger
+	if outputFormat, ok := getLoggerOutputFormat(h.logger); ok {
+		if formatter == nil {
+			formatter = outputFormat.get().Formatter
+		}
+
+		outputFormat.set(formatter, cfg.ReportCaller)
+	}
+
+	setLoggerConfig(h.logger, cfg)
+	cfg.internal().log(h.logger)
+
+	return h.state(), nil
+}
+
+// setLevel changes the level and, with a ttl, schedules going back to
+// the previous one. Any change cancels a pending revert.
+func (h *AdminHandler) setLevel(lvl logrus.Level, ttl time.Duration) {
+	if h.revertTimer != nil {
+		h.revertTimer.Stop()
+		h.revertTimer = nil
+	}
+
+	previous := getLoggerBaseLevel(h.logger)
+	setLoggerBaseLevel(h.logger, lvl)
+
+	if ttl <= 0 {
+		return
+	}
+
+	var timer *time.Timer
+
+	timer = time.AfterFunc(ttl, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+
+		reconfigureMu.Lock()
+		defer reconfigureMu.Unlock()
+
+		// a newer change already replaced this revert
+		if h.revertTimer != timer {
+			return
+		}
+
+		h.revertTimer = nil
+		setLoggerBaseLevel(h.logger, previous)
+
+		cfg, _ := getLoggerConfig(h.logger)
+		cfg.Level = previous.String()
+		setLoggerConfig(h.logger, cfg)
+
+		h.logger.Infof("logrus-configurator: log level reverted to %s", previous)
+	})
+	h.revertTimer = timer
+}
+
+func writeAdminJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,229 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveAdmin(t *testing.T, h http.Handler, method string, body string) (*httptest.ResponseRecorder, map[string]any) {
+	t.Helper()
+
+	req := httptest.NewRequest(method, "/log", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+
+	h.ServeHTTP(rec, req)
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+
+	return rec, result
+}
+
+func TestAdminHandlerGet(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithLevel("warn"), WithFormat("json"), WithReportCaller(true))
+	require.NoError(t, err)
+
+	rec, result := serveAdmin(t, NewAdminHandler(logger), http.MethodGet, "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, map[string]any{"level": "warning", "format": "json", "reportCaller": true}, result)
+}
+
+func TestAdminHandlerUpdate(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithColor("never"))
+	require.NoError(t, err)
+
+	h := NewAdminHandler(logger)
+
+	testCases := []struct {
+		name           string
+		method         string
+		body           string
+		expectedStatus int
+		expected       map[string]any
+	}{
+		{
+			name:           "Level",
+			method:         http.MethodPut,
+			body:           `{"level":"debug"}`,
+			expectedStatus: http.StatusOK,
+			expected:       map[string]any{"level": "debug", "format": "text", "reportCaller": false},
+		},
+		{
+			name:           "Format and caller",
+			method:         http.MethodPost,
+			body:           `{"format":"pretty","reportCaller":true}`,
+			expectedStatus: http.StatusOK,
+			expected:       map[string]any{"level": "debug", "format": "pretty", "reportCaller": true},
+		},
+		{
+			name:           "Invalid level changes nothing",
+			method:         http.MethodPut,
+			body:           `{"level":"loud","format":"json"}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       map[string]any{"error": "loud: invalid log level"},
+		},
+		{
+			name:           "Invalid format",
+			method:         http.MethodPut,
+			body:           `{"format":"xml"}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       map[string]any{"error": "xml: invalid log format"},
+		},
+		{
+			name:           "Invalid ttl",
+			method:         http.MethodPut,
+			body:           `{"level":"trace","ttl":"soon"}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       map[string]any{"error": "soon: invalid ttl"},
+		},
+		{
+			name:           "Ttl without level",
+			method:         http.MethodPut,
+			body:           `{"ttl":"1m"}`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       map[string]any{"error": "ttl needs a level to revert: invalid ttl"},
+		},
+		{
+			name:           "Invalid body",
+			method:         http.MethodPut,
+			body:           `{`,
+			expectedStatus: http.StatusBadRequest,
+			expected:       map[string]any{"error": "invalid request body: unexpected EOF"},
+		},
+		{
+			name:           "Method not allowed",
+			method:         http.MethodDelete,
+			expectedStatus: http.StatusMethodNotAllowed,
+			expected:       map[string]any{"error": "method not allowed"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rec, result := serveAdmin(t, h, tc.method, tc.body)
+			assert.Equal(t, tc.expectedStatus, rec.Code)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.True(t, logger.ReportCaller)
+
+	formatter, ok := logger.Formatter.(*prettyFormatter)
+	require.True(t, ok, "Expected the pretty formatter")
+	assert.False(t, formatter.Color, "Expected the configured color to be kept")
+
+	cfg, ok := getLoggerConfig(logger)
+	require.True(t, ok)
+	assert.Equal(t, "debug", cfg.Level)
+	assert.Equal(t, "pretty", cfg.Format)
+	assert.True(t, cfg.ReportCaller)
+}
+
+func TestAdminHandlerTTL(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithLevel("info"))
+	require.NoError(t, err)
+
+	h := NewAdminHandler(logger)
+
+	rec, _ := serveAdmin(t, h, http.MethodPut, `{"level":"trace","ttl":"50ms"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.InfoLevel
+	}, 5*time.Second, 10*time.Millisecond, "Expected the level to be reverted")
+
+	_, result := serveAdmin(t, h, http.MethodGet, "")
+	assert.Equal(t, "info", result["level"])
+
+	// a newer change cancels the pending revert
+	serveAdmin(t, h, http.MethodPut, `{"level":"debug","ttl":"50ms"}`)
+	serveAdmin(t, h, http.MethodPut, `{"level":"error"}`)
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, logrus.ErrorLevel, logger.GetLevel(), "Expected the revert to be cancelled")
+}
+
+func TestNewAdminHandlerDefaultsToStandardLogger(t *testing.T) {
+	assert.Same(t, logrus.StandardLogger(), NewAdminHandler(nil).logger)
+}
+
+func TestAdminHandlerUpdateWhileLogging(t *testing.T) {
+	unsetEnvs(t)
+
+	var (
+		mu     sync.Mutex
+		buffer bytes.Buffer
+	)
+
+	logger, err := NewLogger(WithHooks(getAllLevelsHook(writerFunc(func(p []byte) (int, error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		return buffer.Write(p)
+	}))))
+	require.NoError(t, err)
+
+	h := NewAdminHandler(logger)
+	done := make(chan struct{})
+
+	var wg sync.WaitGroup
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					logger.Info("busy")
+				}
+			}
+		}()
+	}
+
+	for _, body := range []string{`{"format":"logfmt"}`, `{"reportCaller":true}`, `{"format":"json","reportCaller":false}`} {
+		rec, _ := serveAdmin(t, h, http.MethodPut, body)
+		require.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	close(done)
+	wg.Wait()
+
+	buffer.Reset()
+	logger.Info("after")
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	assert.Contains(t, buffer.String(), `"msg":"after"`, "Expected the outputs to use the new format")
+}
+
+// writerFunc turns a function into an io.Writer
+type writerFunc func(p []byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
@@ -0,0 +1,309 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type overflow string
+
+const (
+	// overflowBlock makes the callers wait for room in the queue
+	overflowBlock overflow = "block"
+	// overflowDropNewest drops the entry being logged
+	overflowDropNewest overflow = "drop-newest"
+	// overflowDropOldest drops the oldest entry in the queue
+	overflowDropOldest overflow = "drop-oldest"
+	// overflowDropBelowLevel drops the entries less severe than the
+	// drop level and makes the callers of the rest wait
+	overflowDropBelowLevel overflow = "drop-below-level"
+)
+
+// AsyncConfig configures the asynchronous dispatch of the output hooks
+type AsyncConfig struct {
+	// Enabled moves the writing to the outputs off the logging goroutines
+	Enabled bool
+	// QueueSize is the number of entries waiting to be written, 1024 when 0
+	QueueSize int
+	// Overflow decides what happens when the queue is full: block (the
+	// default), drop-newest, drop-oldest or drop-below-level
+	Overflow string
+	// DropLevel is the least severe level that isn't dropped
+	// with drop-below-level, warn when empty
+	DropLevel string
+}
+
+// loggerAsyncs keeps the AsyncHook LOG_ASYNC put in front of the
+// outputs of each logger so its drops can be read
+//
+//nolint:gochecknoglobals
+var (
+	loggerAsyncsMu sync.RWMutex
+	loggerAsyncs   = map[*logrus.Logger]*AsyncHook{}
+)
+
+// AsyncHook fires the hook it wraps on a goroutine of its own so slow
+// outputs don't hold up the callers. The entries wait in a bounded
+// queue and the overflow policy decides what happens when it's full.
+type AsyncHook struct {
+	hook      logrus.Hook
+	queue     chan *logrus.Entry
+	overflow  overflow
+	dropLevel logrus.Level
+	dropped   atomic.Uint64
+	done      chan struct{}
+
+	mu     sync.RWMutex
+	closed bool
+
+	pendingMu sync.Mutex
+	pending   int
+	idle      chan struct{}
+}
+
+// NewAsyncHook starts firing the hook asynchronously
+func NewAsyncHook(hook logrus.Hook, cfg AsyncConfig) (*AsyncHook, error) {
+	queueSize := cfg.QueueSize
+	if queueSize == 0 {
+		queueSize = defaultAsyncQueueSize
+	}
+
+	if queueSize < 0 {
+		return nil, errors.Wrapf(errInvalidLogAsync, "queue size %d", queueSize)
+	}
+
+	policy := overflow(cfg.Overflow)
+	switch policy {
+	case "":
+		policy = defaultAsyncOverflow
+	case overflowBlock, overflowDropNewest, overflowDropOldest, overflowDropBelowLevel:
+	default:
+		return nil, errors.Wrapf(errInvalidLogAsync, "overflow %s", cfg.Overflow)
+	}
+
+	dropLevel := level(cfg.DropLevel)
+	if dropLevel == "" {
+		dropLevel = defaultAsyncDropLevel
+	}
+
+	logrusDropLevel, err := getLogrusLevel(dropLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "drop level")
+	}
+
+	idle := make(chan struct{})
+	close(idle)
+
+	h := &AsyncHook{
+		hook:      hook,
+		queue:     make(chan *logrus.Entry, queueSize),
+		overflow:  policy,
+		dropLevel: logrusDropLevel,
+		done:      make(chan struct{}),
+		idle:      idle,
+	}
+
+	go h.run()
+
+	return h, nil
+}
+
+func (h *AsyncHook) Levels() []logrus.Level {
+	return h.hook.Levels()
+}
+
+// Fire queues a copy of the entry as logrus keeps using the original.
+// Once the hook is closed the entries get fired right away. Fatal and
+// panic entries are waited for since the app is about to go down.
+func (h *AsyncHook) Fire(entry *logrus.Entry) error {
+	if err := h.fire(entry); err != nil {
+		return err
+	}
+
+	if entry.Level > logrus.FatalLevel {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exitShutdownTimeout)
+	defer cancel()
+
+	return h.Flush(ctx)
+}
+
+func (h *AsyncHook) fire(entry *logrus.Entry) error {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if h.closed {
+		return h.hook.Fire(entry) //nolint:wrapcheck
+	}
+
+	h.enqueue(copyEntry(entry))
+
+	return nil
+}
+
+// copyEntry copies the parts of the entry the hooks and the formatters
+// use so it can be kept around after logrus is done with the original
+func copyEntry(entry *logrus.Entry) *logrus.Entry {
+	return &logrus.Entry{
+		Logger:  entry.Logger,
+		Data:    maps.Clone(entry.Data),
+		Time:    entry.Time,
+		Level:   entry.Level,
+		Caller:  entry.Caller,
+		Message: entry.Message,
+		Context: entry.Context,
+	}
+}
+
+func (h *AsyncHook) enqueue(entry *logrus.Entry) {
+	h.addPending()
+
+	policy := h.overflow
+	if policy == overflowDropBelowLevel {
+		policy = overflowBlock
+		if entry.Level > h.dropLevel {
+			policy = overflowDropNewest
+		}
+	}
+
+	switch policy {
+	case overflowDropNewest:
+		select {
+		case h.queue <- entry:
+		default:
+			h.drop()
+		}
+	case overflowDropOldest:
+		for {
+			select {
+			case h.queue <- entry:
+				return
+			default:
+			}
+
+			select {
+			case <-h.queue:
+				h.drop()
+			default:
+			}
+		}
+	default:
+		h.queue <- entry
+	}
+}
+
+func (h *AsyncHook) run() {
+	defer close(h.done)
+
+	for entry := range h.queue {
+		if err := h.hook.Fire(entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+		}
+
+		h.donePending()
+	}
+}
+
+func (h *AsyncHook) drop() {
+	h.dropped.Add(1)
+	h.donePending()
+}
+
+func (h *AsyncHook) addPending() {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	if h.pending == 0 {
+		h.idle = make(chan struct{})
+	}
+
+	h.pending++
+}
+
+func (h *AsyncHook) donePending() {
+	h.pendingMu.Lock()
+	defer h.pendingMu.Unlock()
+
+	h.pending--
+	if h.pending == 0 {
+		close(h.idle)
+	}
+}
+
+// Dropped returns the number of entries dropped because the queue was full
+func (h *AsyncHook) Dropped() uint64 {
+	return h.dropped.Load()
+}
+
+// Flush waits for the entries queued so far to be fired
+func (h *AsyncHook) Flush(ctx context.Context) error {
+	h.pendingMu.Lock()
+	idle := h.idle
+	h.pendingMu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to flush async log hook")
+	}
+}
+
+// Close fires the entries left in the queue and stops the goroutine.
+// The wrapped hook is left open.
+func (h *AsyncHook) Close() error {
+	h.mu.Lock()
+	if !h.closed {
+		h.closed = true
+		close(h.queue)
+	}
+	h.mu.Unlock()
+
+	<-h.done
+
+	return nil
+}
+
+// Dropped returns the number of entries LOG_ASYNC dropped for the standard
+// logger because its queue was full since the logger was last configured
+func Dropped() uint64 {
+	return LoggerDropped(logrus.StandardLogger())
+}
+
+// LoggerDropped is Dropped for any logger, 0 when it isn't async
+func LoggerDropped(logger *logrus.Logger) uint64 {
+	if async := getLoggerAsync(logger); async != nil {
+		return async.Dropped()
+	}
+
+	return 0
+}
+
+func setLoggerAsync(logger *logrus.Logger, hook *AsyncHook) {
+	loggerAsyncsMu.Lock()
+	defer loggerAsyncsMu.Unlock()
+
+	if hook == nil {
+		delete(loggerAsyncs, logger)
+
+		return
+	}
+
+	loggerAsyncs[logger] = hook
+}
+
+func getLoggerAsync(logger *logrus.Logger) *AsyncHook {
+	loggerAsyncsMu.RLock()
+	defer loggerAsyncsMu.RUnlock()
+
+	return loggerAsyncs[logger]
+}
@@ -0,0 +1,278 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gatedHook records the messages it's fired with
+// but waits for the gate to open first
+type gatedHook struct {
+	gate    chan struct{}
+	started chan struct{}
+
+	mu       sync.Mutex
+	messages []string
+	data     []logrus.Fields
+}
+
+func newGatedHook() *gatedHook {
+	return &gatedHook{gate: make(chan struct{}), started: make(chan struct{}, 1)}
+}
+
+func (h *gatedHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *gatedHook) Fire(entry *logrus.Entry) error {
+	select {
+	case h.started <- struct{}{}:
+	default:
+	}
+
+	<-h.gate
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.messages = append(h.messages, entry.Message)
+	h.data = append(h.data, entry.Data)
+
+	return nil
+}
+
+func (h *gatedHook) getMessages() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return append([]string{}, h.messages...)
+}
+
+func TestAsyncHookOverflow(t *testing.T) {
+	testCases := []struct {
+		name            string
+		overflow        string
+		expected        []string
+		expectedDropped uint64
+	}{
+		{
+			name:     "Block",
+			overflow: "block",
+			expected: []string{"1", "2", "3", "4"},
+		},
+		{
+			name:            "Drop newest",
+			overflow:        "drop-newest",
+			expected:        []string{"1", "2", "3"},
+			expectedDropped: 1,
+		},
+		{
+			name:            "Drop oldest",
+			overflow:        "drop-oldest",
+			expected:        []string{"1", "3", "4"},
+			expectedDropped: 1,
+		},
+		{
+			name:            "Drop below level",
+			overflow:        "drop-below-level",
+			expected:        []string{"1", "2", "3", "5"},
+			expectedDropped: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hook := newGatedHook()
+
+			h, err := NewAsyncHook(hook, AsyncConfig{QueueSize: 2, Overflow: tc.overflow})
+			require.NoError(t, err)
+
+			t.Cleanup(func() { _ = h.Close() })
+
+			// the first entry keeps the goroutine busy
+			// while the next two fill the queue
+			require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "1"}))
+			<-hook.started
+			require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "2"}))
+			require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "3"}))
+
+			blocked := make(chan struct{})
+
+			go func() {
+				defer close(blocked)
+
+				if tc.overflow == "drop-below-level" {
+					_ = h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "4"})
+					_ = h.Fire(&logrus.Entry{Level: logrus.ErrorLevel, Message: "5"})
+
+					return
+				}
+
+				_ = h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "4"})
+			}()
+
+			switch tc.overflow {
+			case "drop-newest", "drop-oldest":
+				<-blocked
+			default:
+				select {
+				case <-blocked:
+					t.Fatal("Expected the caller to wait for room in the queue")
+				case <-time.After(50 * time.Millisecond):
+				}
+			}
+
+			close(hook.gate)
+			<-blocked
+
+			require.NoError(t, h.Flush(context.Background()))
+			assert.Equal(t, tc.expected, hook.getMessages())
+			assert.Equal(t, tc.expectedDropped, h.Dropped())
+		})
+	}
+}
+
+func TestAsyncHookFiresCopies(t *testing.T) {
+	hook := newGatedHook()
+	close(hook.gate)
+
+	h, err := NewAsyncHook(hook, AsyncConfig{})
+	require.NoError(t, err)
+
+	entry := &logrus.Entry{Level: logrus.InfoLevel, Message: "copied", Data: logrus.Fields{"key": "before"}}
+	require.NoError(t, h.Fire(entry))
+
+	entry.Data["key"] = "after"
+
+	require.NoError(t, h.Close())
+	assert.Equal(t, []logrus.Fields{{"key": "before"}}, hook.data)
+}
+
+func TestAsyncHookClose(t *testing.T) {
+	hook := newGatedHook()
+
+	h, err := NewAsyncHook(hook, AsyncConfig{QueueSize: 10})
+	require.NoError(t, err)
+
+	for _, msg := range []string{"1", "2", "3"} {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: msg}))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, h.Flush(ctx), context.DeadlineExceeded)
+
+	close(hook.gate)
+	require.NoError(t, h.Close())
+	assert.Equal(t, []string{"1", "2", "3"}, hook.getMessages(), "Expected the queue to be drained")
+
+	// entries fired after closing are fired right away
+	require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "4"}))
+	assert.Equal(t, []string{"1", "2", "3", "4"}, hook.getMessages())
+	require.NoError(t, h.Close())
+}
+
+func TestNewAsyncHookInvalid(t *testing.T) {
+	testCases := []struct {
+		name          string
+		cfg           AsyncConfig
+		expectedError string
+	}{
+		{
+			name:          "Queue size",
+			cfg:           AsyncConfig{QueueSize: -1},
+			expectedError: "queue size -1: invalid log async config",
+		},
+		{
+			name:          "Overflow",
+			cfg:           AsyncConfig{Overflow: "spill"},
+			expectedError: "overflow spill: invalid log async config",
+		},
+		{
+			name:          "Drop level",
+			cfg:           AsyncConfig{DropLevel: "loud"},
+			expectedError: "drop level: loud: invalid log level",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewAsyncHook(newGatedHook(), tc.cfg)
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestConfigureAsync(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv(configKeyLogAsync, "true")
+	t.Setenv(configKeyLogAsyncOverflow, "drop-oldest")
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	logger, err := NewLogger(WithHooks(hook))
+	require.NoError(t, err)
+
+	require.Len(t, getConfiguredHooks(logger)[logrus.InfoLevel], 1)
+
+	async, ok := getConfiguredHooks(logger)[logrus.InfoLevel][0].(*AsyncHook)
+	require.True(t, ok, "Expected the output hooks to be async")
+	assert.Equal(t, overflowDropOldest, async.overflow)
+	assert.Equal(t, defaultAsyncQueueSize, cap(async.queue))
+
+	logger.Info("queued")
+	require.NoError(t, async.Flush(context.Background()))
+	assert.Equal(t, []string{"queued"}, hook.getMessages())
+
+	// reconfiguring drains and stops the previous queue
+	require.NoError(t, ConfigureLogger(logger, WithHooks(hook), WithAsync(AsyncConfig{})))
+
+	select {
+	case <-async.done:
+	default:
+		t.Fatal("Expected the previous async hook to be closed")
+	}
+
+	assert.NotContains(t, logger.Hooks[logrus.InfoLevel], logrus.Hook(async))
+
+	_, err = NewLogger(WithAsync(AsyncConfig{Enabled: true, Overflow: "spill"}))
+	require.EqualError(t, err, "failed to set log outputs: overflow spill: invalid log async config")
+}
+
+func TestLoggerDropped(t *testing.T) {
+	unsetEnvs(t)
+
+	hook := newGatedHook()
+
+	logger, err := NewLogger(
+		WithHooks(hook),
+		WithAsync(AsyncConfig{Enabled: true, QueueSize: 1, Overflow: string(overflowDropNewest)}),
+	)
+	require.NoError(t, err)
+
+	logger.Info("fired")
+	<-hook.started
+
+	logger.Info("queued")
+	logger.Info("dropped")
+	logger.Info("dropped too")
+
+	assert.Equal(t, uint64(2), LoggerDropped(logger))
+
+	close(hook.gate)
+
+	require.NoError(t, ConfigureLogger(logger, WithHooks(hook), WithAsync(AsyncConfig{})))
+	assert.Equal(t, []string{"fired", "queued"}, hook.getMessages())
+	assert.Zero(t, LoggerDropped(logger), "Expected no drops without async")
+
+	assert.Zero(t, LoggerDropped(logrus.New()))
+	assert.Equal(t, LoggerDropped(logrus.StandardLogger()), Dropped())
+}
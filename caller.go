@@ -0,0 +1,119 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+const (
+	logrusPackage  = "github.com/sirupsen/logrus"
+	maxCallerDepth = 32
+	// shortCallerDepth reaches past logrus from a hook it fires
+	shortCallerDepth = 16
+)
+
+type callerContextKey struct{}
+
+// callerFunctions caches the functions of each pc, inlined ones
+// included, so only the first call from a call site resolves them
+//
+//nolint:gochecknoglobals
+var callerFunctions sync.Map
+
+// withCaller stores the frame in the context so contextHook reports
+// it instead of the bridge that passed the entry to logrus
+func withCaller(ctx context.Context, frame runtime.Frame) context.Context {
+	return context.WithValue(ctx, callerContextKey{}, &frame)
+}
+
+func getContextCaller(ctx context.Context) *runtime.Frame {
+	if ctx == nil {
+		return nil
+	}
+
+	frame, _ := ctx.Value(callerContextKey{}).(*runtime.Frame)
+
+	return frame
+}
+
+// getCallerAfter returns the first frame after the ones of the package
+// on the stack, e.g. the frame logrus reports when caller reporting is on
+func getCallerAfter(pkg string) runtime.Frame {
+	pcs := make([]uintptr, maxCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(1, pcs)])
+
+	inPackage := false
+
+	for {
+		frame, more := frames.Next()
+
+		if strings.HasPrefix(frame.Function, pkg+".") {
+			inPackage = true
+		} else if inPackage {
+			return frame
+		}
+
+		if !more {
+			return runtime.Frame{}
+		}
+	}
+}
+
+// getCallerFunctionAfter is getCallerAfter for when only the function
+// name is needed. Every pc gets resolved only once and the stack only
+// gets walked further than the usual depth of a log call if it has to.
+func getCallerFunctionAfter(pkg string) string {
+	var pcs [maxCallerDepth]uintptr
+
+	for _, depth := range []int{shortCallerDepth, maxCallerDepth} {
+		n := runtime.Callers(1, pcs[:depth])
+		if function := getFunctionAfter(pkg, pcs[:n]); function != "" || n < depth {
+			return function
+		}
+	}
+
+	return ""
+}
+
+func getFunctionAfter(pkg string, pcs []uintptr) string {
+	inPackage := false
+
+	for _, pc := range pcs {
+		for _, function := range getPCFunctions(pc) {
+			if strings.HasPrefix(function, pkg+".") {
+				inPackage = true
+			} else if inPackage {
+				return function
+			}
+		}
+	}
+
+	return ""
+}
+
+// getPCFunctions returns the functions at the pc, innermost first
+func getPCFunctions(pc uintptr) []string {
+	if cached, ok := callerFunctions.Load(pc); ok {
+		functions, _ := cached.([]string)
+
+		return functions
+	}
+
+	functions := []string{}
+	frames := runtime.CallersFrames([]uintptr{pc})
+
+	for {
+		frame, more := frames.Next()
+		functions = append(functions, frame.Function)
+
+		if !more {
+			break
+		}
+	}
+
+	callerFunctions.Store(pc, functions)
+
+	return functions
+}
@@ -4,12 +4,67 @@ import (
 	"os"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 )
 
-func unsetEnvs(t *testing.T) {
+func unsetEnvs(t testing.TB) {
 	t.Helper()
 
 	require.NoError(t, os.Unsetenv(configKeyLogLevel), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogLevels), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogSampling), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogDedupeWindow), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogRateLimit), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogRedact), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogRedactFields), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogRedactMode), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogRedactHashKey), "Unexpected error")
 	require.NoError(t, os.Unsetenv(configKeyLogFormat), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogCaller), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogColor), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogOutput), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogSlog), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogCaptureStdlib), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogCaptureStdlibLevel), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogFile), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogSplit), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogStderrLevels), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogConfigFile), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogAsync), "Unexpected error")
+	require.NoError(t, os.Unsetenv(configKeyLogAsyncOverflow), "Unexpected error")
+}
+
+// getConfiguredHooks returns the hooks of the logger
+// without the contextHook every configured logger has
+func getConfiguredHooks(logger *logrus.Logger) logrus.LevelHooks {
+	hooks := logrus.LevelHooks{}
+
+	for lvl, levelHooks := range logger.Hooks {
+		for _, hook := range levelHooks {
+			if _, ok := hook.(contextHook); !ok {
+				hooks[lvl] = append(hooks[lvl], hook)
+			}
+		}
+	}
+
+	return hooks
+}
+
+// getLevelOutputHooks returns the configured hooks of the level
+// with the ones wrapped in an outputsHook unwrapped
+func getLevelOutputHooks(logger *logrus.Logger, lvl logrus.Level) []logrus.Hook {
+	hooks := []logrus.Hook{}
+
+	for _, hook := range getConfiguredHooks(logger)[lvl] {
+		if outputs, ok := hook.(*outputsHook); ok {
+			hooks = append(hooks, outputs.hooks[lvl]...)
+
+			continue
+		}
+
+		hooks = append(hooks, hook)
+	}
+
+	return hooks
 }
@@ -0,0 +1,242 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v3"
+)
+
+// configFilePollInterval is how often WatchConfigFile checks the file
+//
+//nolint:gochecknoglobals
+var configFilePollInterval = time.Second
+
+// configFile is what a YAML, JSON or TOML config file can hold.
+// Unset values leave the defaults alone and env vars win over it.
+type configFile struct {
+	Level        *string           `json:"level"        toml:"level"        yaml:"level"`
+	Levels       map[string]string `json:"levels"       toml:"levels"       yaml:"levels"`
+	Format       *string           `json:"format"       toml:"format"       yaml:"format"`
+	Caller       *bool             `json:"caller"       toml:"caller"       yaml:"caller"`
+	Color        *string           `json:"color"        toml:"color"        yaml:"color"`
+	Outputs      []string          `json:"outputs"      toml:"outputs"      yaml:"outputs"`
+	Split        *string           `json:"split"        toml:"split"        yaml:"split"`
+	StderrLevels []string          `json:"stderrLevels" toml:"stderrLevels" yaml:"stderrLevels"`
+	File         *configFileFile   `json:"file"         toml:"file"         yaml:"file"`
+}
+
+type configFileFile struct {
+	Path       *string `json:"path"       toml:"path"       yaml:"path"`
+	MaxSize    *int    `json:"maxSize"    toml:"maxSize"    yaml:"maxSize"`
+	MaxAge     *string `json:"maxAge"     toml:"maxAge"     yaml:"maxAge"`
+	MaxBackups *int    `json:"maxBackups" toml:"maxBackups" yaml:"maxBackups"`
+	Compress   *bool   `json:"compress"   toml:"compress"   yaml:"compress"`
+}
+
+func readConfigFile(path string) (configFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return configFile{}, errors.Wrap(err, "failed to read log config file")
+	}
+
+	return parseConfigFile(path, data)
+}
+
+// parseConfigFile decodes the file by its extension
+// rejecting the keys it doesn't know about
+func parseConfigFile(path string, data []byte) (configFile, error) {
+	f := configFile{}
+
+	var err error
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&f)
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+
+		// an empty file is a valid file that sets nothing
+		if err = decoder.Decode(&f); errors.Is(err, io.EOF) {
+			err = nil
+		}
+	case ".toml":
+		var meta toml.MetaData
+
+		meta, err = toml.Decode(string(data), &f)
+		if undecoded := meta.Undecoded(); err == nil && len(undecoded) > 0 {
+			err = errors.Errorf("unknown keys %v", undecoded)
+		}
+	default:
+		return f, errors.Wrap(errInvalidConfigFile, path)
+	}
+
+	if err != nil {
+		return f, errors.Wrapf(err, "failed to parse log config file %s", path)
+	}
+
+	return f, nil
+}
+
+// merge sets the values the file has on top of the
+// env config unless their env vars are set
+func (f configFile) merge(c *config, prefix string) error {
+	envSet := func(key string) bool {
+		_, ok := os.LookupEnv(envKey(prefix, key))
+
+		return ok
+	}
+
+	if f.Level != nil && !envSet(configKeyLogLevel) {
+		c.Level = level(*f.Level)
+	}
+
+	if f.Levels != nil && !envSet(configKeyLogLevels) {
+		c.PackageLevels = make([]string, 0, len(f.Levels))
+		for pattern, lvl := range f.Levels {
+			c.PackageLevels = append(c.PackageLevels, pattern+"="+lvl)
+		}
+
+		sort.Strings(c.PackageLevels)
+	}
+
+	if f.Format != nil && !envSet(configKeyLogFormat) {
+		c.Format = format(*f.Format)
+	}
+
+	if f.Caller != nil && !envSet(configKeyLogCaller) {
+		c.ReportCaller = *f.Caller
+	}
+
+	if f.Color != nil && !envSet(configKeyLogColor) {
+		c.Color = color(*f.Color)
+	}
+
+	if f.Outputs != nil && !envSet(configKeyLogOutput) {
+		c.Outputs = f.Outputs
+	}
+
+	if f.Split != nil && !envSet(configKeyLogSplit) {
+		c.Split = split(*f.Split)
+	}
+
+	if f.StderrLevels != nil && !envSet(configKeyLogStderrLevels) {
+		c.StderrLevels = f.StderrLevels
+	}
+
+	if f.File == nil {
+		return nil
+	}
+
+	return f.File.merge(c, envSet)
+}
+
+func (f configFileFile) merge(c *config, envSet func(string) bool) error {
+	if f.Path != nil && !envSet(configKeyLogFile) {
+		c.File = *f.Path
+	}
+
+	if f.MaxSize != nil && !envSet(configKeyLogFileMaxSize) {
+		c.FileMaxSize = *f.MaxSize
+	}
+
+	if f.MaxAge != nil && !envSet(configKeyLogFileMaxAge) {
+		maxAge, err := time.ParseDuration(*f.MaxAge)
+		if err != nil {
+			return errors.Wrap(err, "failed to parse log config file max age")
+		}
+
+		c.FileMaxAge = maxAge
+	}
+
+	if f.MaxBackups != nil && !envSet(configKeyLogFileMaxBackups) {
+		c.FileMaxBackups = *f.MaxBackups
+	}
+
+	if f.Compress != nil && !envSet(configKeyLogFileCompress) {
+		c.FileCompress = *f.Compress
+	}
+
+	return nil
+}
+
+// WatchConfigFile polls the config file of the standard logger and
+// reconfigures it once a change to the file settles, keeping its env
+// prefix, config file and hooks with the given options applied on top.
+// Invalid edits are logged and the old config is kept. The returned
+// function stops watching.
+func WatchConfigFile(opts ...Option) func() {
+	return WatchLoggerConfigFile(logrus.StandardLogger(), opts...)
+}
+
+// WatchLoggerConfigFile does what WatchConfigFile does but for the given logger
+func WatchLoggerConfigFile(logger *logrus.Logger, opts ...Option) func() {
+	ticker := time.NewTicker(configFilePollInterval)
+	done := make(chan struct{})
+	last := readLoggerConfigFile(logger)
+	pending := last
+
+	go func() {
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				// wait for a change to stay the same for one more poll
+				// so a file caught in the middle of a write isn't loaded
+				current := readLoggerConfigFile(logger)
+				if bytes.Equal(current, last) || !bytes.Equal(current, pending) {
+					pending = current
+
+					continue
+				}
+
+				last = current
+
+				if err := reloadLogger(logger, opts...); err != nil {
+					logger.WithError(err).Error("logrus-configurator: failed to reload log config, keeping the old one")
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			close(done)
+		})
+	}
+}
+
+// readLoggerConfigFile returns the content of the config file the logger
+// was last configured from. A missing or unreadable file reads as nothing
+// so it only triggers a reload when it goes away or comes back.
+func readLoggerConfigFile(logger *logrus.Logger) []byte {
+	cfg, _ := getLoggerConfig(logger)
+	if cfg.ConfigFile == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(cfg.ConfigFile)
+	if err != nil {
+		return nil
+	}
+
+	return data
+}
@@ -0,0 +1,203 @@
+package logrusconfigurator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, path string, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestParseConfigFile(t *testing.T) {
+	expected := defaultConfig()
+	expected.Level = levelDebug
+	expected.PackageLevels = []string{"db=debug", "http=warn"}
+	expected.Format = formatJSON
+	expected.ReportCaller = true
+	expected.Outputs = []string{outputStdout}
+	expected.File = "/var/log/app.log"
+	expected.FileMaxAge = 24 * time.Hour
+
+	testCases := []struct {
+		name          string
+		path          string
+		content       string
+		expectedError string
+	}{
+		{
+			name: "YAML",
+			path: "log.yaml",
+			content: `level: debug
+format: json
+caller: true
+outputs: [stdout]
+levels:
+  http: warn
+  db: debug
+file:
+  path: /var/log/app.log
+  maxAge: 24h
+`,
+		},
+		{
+			name: "JSON",
+			path: "log.json",
+			content: `{"level":"debug","format":"json","caller":true,"outputs":["stdout"],
+"levels":{"http":"warn","db":"debug"},"file":{"path":"/var/log/app.log","maxAge":"24h"}}`,
+		},
+		{
+			name: "TOML",
+			path: "log.toml",
+			content: `level = "debug"
+format = "json"
+caller = true
+outputs = ["stdout"]
+
+[levels]
+http = "warn"
+db = "debug"
+
+[file]
+path = "/var/log/app.log"
+maxAge = "24h"
+`,
+		},
+		{
+			name:          "Unknown YAML key",
+			path:          "log.yml",
+			content:       "levle: debug\n",
+			expectedError: "failed to parse log config file log.yml",
+		},
+		{
+			name:          "Unknown JSON key",
+			path:          "log.json",
+			content:       `{"levle":"debug"}`,
+			expectedError: "failed to parse log config file log.json",
+		},
+		{
+			name:          "Unknown TOML key",
+			path:          "log.toml",
+			content:       `levle = "debug"`,
+			expectedError: "failed to parse log config file log.toml: unknown keys [levle]",
+		},
+		{
+			name:          "Unknown extension",
+			path:          "log.ini",
+			expectedError: "log.ini: invalid log config file",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			unsetEnvs(t)
+
+			f, err := parseConfigFile(tc.path, []byte(tc.content))
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			c := defaultConfig()
+			require.NoError(t, f.merge(&c, ""))
+			assert.Equal(t, expected, c)
+		})
+	}
+}
+
+func TestConfigFileMergeInvalidMaxAge(t *testing.T) {
+	unsetEnvs(t)
+
+	f, err := parseConfigFile("log.yaml", []byte("file:\n  maxAge: forever\n"))
+	require.NoError(t, err)
+
+	c := defaultConfig()
+	require.ErrorContains(t, f.merge(&c, ""), "failed to parse log config file max age")
+}
+
+func TestConfigureConfigFile(t *testing.T) {
+	unsetEnvs(t)
+
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	writeConfigFile(t, path, "level: debug\nformat: json\ncaller: true\n")
+
+	t.Setenv("APP_"+configKeyLogConfigFile, path)
+	t.Setenv("APP_"+configKeyLogFormat, "logfmt")
+
+	logger, err := NewLogger(WithEnvPrefix("APP"), WithReportCaller(false), WithHooks())
+	require.NoError(t, err)
+
+	// the file beats the defaults, the env beats the file
+	// and the options beat them all
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.IsType(t, &logfmtFormatter{}, logger.Formatter)
+	assert.False(t, logger.ReportCaller)
+
+	other := filepath.Join(t.TempDir(), "log.json")
+	writeConfigFile(t, other, `{"level":"error"}`)
+
+	require.NoError(t, ConfigureLogger(logger, WithEnvPrefix("APP"), WithConfigFile(other), WithHooks()))
+	assert.Equal(t, logrus.ErrorLevel, logger.GetLevel())
+
+	_, err = NewLogger(WithConfigFile(filepath.Join(t.TempDir(), "missing.yaml")))
+	require.ErrorContains(t, err, "failed to read log config file")
+}
+
+func TestWatchLoggerConfigFile(t *testing.T) {
+	unsetEnvs(t)
+
+	pollInterval := configFilePollInterval
+	configFilePollInterval = 10 * time.Millisecond
+
+	t.Cleanup(func() { configFilePollInterval = pollInterval })
+
+	path := filepath.Join(t.TempDir(), "log.yaml")
+	writeConfigFile(t, path, "level: info\n")
+
+	logger, err := NewLogger(WithConfigFile(path), WithHooks())
+	require.NoError(t, err)
+
+	// the config file and the hooks come from the logger's config
+	stop := WatchLoggerConfigFile(logger)
+	defer stop()
+
+	writeConfigFile(t, path, "level: debug\n")
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.DebugLevel
+	}, time.Second, 10*time.Millisecond)
+
+	// an invalid edit keeps the old config
+	writeConfigFile(t, path, "level: loud\n")
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+
+	writeConfigFile(t, path, "level: warn\n")
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.WarnLevel
+	}, time.Second, 10*time.Millisecond)
+
+	cfg, ok := getLoggerConfig(logger)
+	require.True(t, ok)
+	assert.Equal(t, path, cfg.ConfigFile, "Expected the config file to be kept")
+	assert.Empty(t, getConfiguredHooks(logger), "Expected the hooks to be kept")
+
+	writeConfigFile(t, path, "level: error\n")
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.ErrorLevel
+	}, time.Second, 10*time.Millisecond, "Expected later edits to be picked up too")
+
+	stop()
+	stop()
+}
@@ -0,0 +1,122 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+type entryContextKey struct{}
+
+// contextField is a context key whose value gets logged as the field
+type contextField struct {
+	key   any
+	field string
+}
+
+// contextFields gets copied on every registration and swapped in
+// whole so the hooks can read it without a lock
+//
+//nolint:gochecknoglobals
+var (
+	contextFieldsMu sync.Mutex
+	contextFields   atomic.Pointer[[]contextField]
+)
+
+// WithContext returns a copy of the context carrying the entry
+func WithContext(ctx context.Context, entry *logrus.Entry) context.Context {
+	return context.WithValue(ctx, entryContextKey{}, entry)
+}
+
+// FromContext returns the entry the context carries or a new one for
+// the standard logger, with the context attached either way so the
+// registered context fields get logged
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry, ok := ctx.Value(entryContextKey{}).(*logrus.Entry)
+	if !ok {
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	}
+
+	return entry.WithContext(ctx)
+}
+
+// RegisterContextField makes the value of the context key show up as
+// the field in every entry logged with that context, e.g. through
+// FromContext or logger.WithContext. Fields set on the entry win.
+func RegisterContextField(key any, field string) {
+	contextFieldsMu.Lock()
+
+	fields := slices.Clone(getContextFields())
+
+	i := slices.IndexFunc(fields, func(f contextField) bool { return f.key == key })
+	if i < 0 {
+		fields = append(fields, contextField{key: key, field: field})
+	} else {
+		fields[i].field = field
+	}
+
+	contextFields.Store(&fields)
+	contextFieldsMu.Unlock()
+}
+
+func getContextFields() []contextField {
+	if fields := contextFields.Load(); fields != nil {
+		return *fields
+	}
+
+	return nil
+}
+
+// contextHook fills the entry in from its context: the registered
+// context fields get added and the caller logrus found is replaced
+// with the one a bridge stored in the context
+type contextHook struct{}
+
+func (contextHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (contextHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil {
+		return nil
+	}
+
+	if frame := getContextCaller(entry.Context); frame != nil {
+		entry.Caller = frame
+	}
+
+	for _, f := range getContextFields() {
+		if _, ok := entry.Data[f.field]; ok {
+			continue
+		}
+
+		if value := entry.Context.Value(f.key); value != nil {
+			entry.Data[f.field] = value
+		}
+	}
+
+	return nil
+}
+
+// addContextHook adds a contextHook to a logger that isn't configured
+// by this package. The configured ones have it in front of their other
+// hooks already.
+func addContextHook(logger *logrus.Logger) {
+	if _, ok := getLoggerConfig(logger); ok {
+		return
+	}
+
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+
+	for _, hook := range logger.Hooks[logrus.PanicLevel] {
+		if _, ok := hook.(contextHook); ok {
+			return
+		}
+	}
+
+	logger.AddHook(contextHook{})
+}
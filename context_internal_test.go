@@ -0,0 +1,123 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testContextKey string
+
+func resetContextFields(t *testing.T) {
+	t.Helper()
+
+	t.Cleanup(func() {
+		contextFields.Store(nil)
+
+		require.NoError(t, Configure())
+	})
+}
+
+func TestWithContext(t *testing.T) {
+	logger := logrus.New()
+	entry := logger.WithField("component", "db")
+
+	ctx := WithContext(t.Context(), entry)
+
+	fromContext := FromContext(ctx)
+	assert.Same(t, logger, fromContext.Logger)
+	assert.Equal(t, logrus.Fields{"component": "db"}, fromContext.Data)
+	assert.Equal(t, ctx, fromContext.Context)
+
+	fromContext = FromContext(t.Context())
+	assert.Same(t, logrus.StandardLogger(), fromContext.Logger)
+	assert.Empty(t, fromContext.Data)
+}
+
+func TestContextFields(t *testing.T) {
+	unsetEnvs(t)
+	resetContextFields(t)
+
+	requestIDKey := testContextKey("request-id")
+	userIDKey := testContextKey("user-id")
+
+	var buf bytes.Buffer
+
+	// configured before the fields are registered
+	logger, err := NewLogger(WithFormat("json"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	for _, lvl := range logrus.AllLevels {
+		require.NotEmpty(t, logger.Hooks[lvl])
+		assert.IsType(t, contextHook{}, logger.Hooks[lvl][0], "Expected the contextHook in front for %v", lvl)
+	}
+
+	RegisterContextField(requestIDKey, "request_id")
+	RegisterContextField(userIDKey, "user")
+	RegisterContextField(userIDKey, "user_id")
+
+	ctx := context.WithValue(t.Context(), requestIDKey, "abc")
+	ctx = context.WithValue(ctx, userIDKey, 42)
+
+	logger.WithContext(ctx).Info("handled")
+	assert.Contains(t, buf.String(), `"request_id":"abc"`)
+	assert.Contains(t, buf.String(), `"user_id":42`)
+	assert.NotContains(t, buf.String(), `"user":`)
+
+	// the fields set on the entry win
+	buf.Reset()
+	FromContext(WithContext(ctx, logger.WithField("request_id", "explicit"))).Info("handled")
+	assert.Contains(t, buf.String(), `"request_id":"explicit"`)
+	assert.Contains(t, buf.String(), `"user_id":42`)
+
+	// configured after the fields are registered, in text this time
+	other, err := NewLogger(WithFormat("text"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	buf.Reset()
+	other.WithContext(ctx).Info("handled")
+	assert.Contains(t, buf.String(), "request_id=abc")
+
+	buf.Reset()
+	other.Info("no context")
+	assert.NotContains(t, buf.String(), "request_id")
+}
+
+func TestRegisterContextFieldWhileLogging(t *testing.T) {
+	unsetEnvs(t)
+	resetContextFields(t)
+
+	userIDKey := testContextKey("user-id")
+	RegisterContextField(userIDKey, "user")
+
+	logger, err := NewLogger(WithHooks(getAllLevelsHook(io.Discard)))
+	require.NoError(t, err)
+
+	ctx := context.WithValue(t.Context(), userIDKey, 42)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := range 1000 {
+			RegisterContextField(userIDKey, fmt.Sprintf("user_%d", i%2))
+		}
+	}()
+
+	for logging := true; logging; {
+		select {
+		case <-done:
+			logging = false
+		default:
+			logger.WithContext(ctx).Info("handled")
+		}
+	}
+
+	assert.Equal(t, []contextField{{key: userIDKey, field: "user_1"}}, getContextFields())
+}
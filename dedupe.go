@@ -0,0 +1,188 @@
+package logrusconfigurator
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const repeatedField = "repeated"
+
+// DedupeConfig collapses the entries repeated within a window
+type DedupeConfig struct {
+	// Window is how long the repeats of an entry get held back, 0 disables it
+	Window time.Duration
+	// Fields are the fields that tell entries apart on top
+	// of the level and the message, e.g. component
+	Fields []string
+}
+
+// dedupeState is the first entry logged with a key and what came after
+type dedupeState struct {
+	start    time.Time
+	repeated int
+	last     *logrus.Entry
+}
+
+// dedupeHook sits in front of the output hooks and holds back the
+// entries repeated within the window. Once the window is over the
+// last of them gets logged with the number of repeats. Panic and
+// fatal entries always go through, after the repeats held back so far,
+// as they're the last ones the process gets to log.
+type dedupeHook struct {
+	window time.Duration
+	fields []string
+	hooks  logrus.LevelHooks
+	now    func() time.Time
+
+	mu   sync.Mutex
+	seen map[string]*dedupeState
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newDedupeHook(cfg DedupeConfig, hooks ...logrus.Hook) *dedupeHook {
+	h := &dedupeHook{
+		window: cfg.Window,
+		fields: cfg.Fields,
+		hooks:  make(logrus.LevelHooks),
+		now:    time.Now,
+		seen:   map[string]*dedupeState{},
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+
+	for _, hook := range hooks {
+		h.hooks.Add(hook)
+	}
+
+	go h.run()
+
+	return h
+}
+
+func (h *dedupeHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *dedupeHook) Fire(entry *logrus.Entry) error {
+	if entry.Level <= logrus.FatalLevel {
+		h.flush(true)
+
+		return h.hooks.Fire(entry.Level, entry) //nolint:wrapcheck
+	}
+
+	key := h.key(entry)
+	now := h.now()
+
+	h.mu.Lock()
+
+	state, ok := h.seen[key]
+	if ok && now.Sub(state.start) < h.window {
+		state.repeated++
+		state.last = copyEntry(entry)
+		h.mu.Unlock()
+
+		return nil
+	}
+
+	h.seen[key] = &dedupeState{start: now}
+	h.mu.Unlock()
+
+	if ok {
+		h.fireRepeated(state)
+	}
+
+	return h.hooks.Fire(entry.Level, entry) //nolint:wrapcheck
+}
+
+// key tells the entries apart by level, message and the configured fields
+func (h *dedupeHook) key(entry *logrus.Entry) string {
+	var b strings.Builder
+
+	b.WriteString(entry.Level.String())
+	b.WriteByte(0)
+	b.WriteString(entry.Message)
+
+	for _, field := range h.fields {
+		b.WriteByte(0)
+
+		if value, ok := entry.Data[field]; ok {
+			fmt.Fprint(&b, value)
+		}
+	}
+
+	return b.String()
+}
+
+func (h *dedupeHook) run() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(h.window)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.flush(false)
+		case <-h.stop:
+			h.flush(true)
+
+			return
+		}
+	}
+}
+
+// flush logs the repeats held back by the windows that are
+// over, or by all of them when the hook is being closed
+func (h *dedupeHook) flush(all bool) {
+	now := h.now()
+	expired := []*dedupeState{}
+
+	h.mu.Lock()
+
+	for key, state := range h.seen {
+		if all || now.Sub(state.start) >= h.window {
+			expired = append(expired, state)
+			delete(h.seen, key)
+		}
+	}
+
+	h.mu.Unlock()
+
+	for _, state := range expired {
+		h.fireRepeated(state)
+	}
+}
+
+func (h *dedupeHook) fireRepeated(state *dedupeState) {
+	if state.repeated == 0 {
+		return
+	}
+
+	entry := state.last
+	if entry.Data == nil {
+		entry.Data = logrus.Fields{}
+	}
+
+	entry.Data[repeatedField] = state.repeated
+
+	if err := h.hooks.Fire(entry.Level, entry); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+	}
+}
+
+// Close logs the repeats still held back and stops the goroutine
+func (h *dedupeHook) Close() error {
+	h.closeOnce.Do(func() { close(h.stop) })
+
+	<-h.done
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package logrusconfigurator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupeHook(t *testing.T) {
+	hook := newGatedHook()
+	close(hook.gate)
+
+	now := time.Now()
+
+	h := newDedupeHook(DedupeConfig{Window: time.Hour, Fields: []string{"component"}}, hook)
+	h.now = func() time.Time { return now }
+
+	fire := func(lvl logrus.Level, msg string, fields logrus.Fields) {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: lvl, Message: msg, Data: fields}))
+	}
+
+	for range 3 {
+		fire(logrus.ErrorLevel, "db down", logrus.Fields{"component": "db", "attempt": 1})
+	}
+
+	fire(logrus.ErrorLevel, "db down", logrus.Fields{"component": "cache"})
+	fire(logrus.WarnLevel, "db down", logrus.Fields{"component": "db"})
+	fire(logrus.ErrorLevel, "db down", logrus.Fields{"component": "db", "attempt": 4})
+
+	assert.Equal(t, []string{"db down", "db down", "db down"}, hook.getMessages(),
+		"Expected the repeats to be held back")
+
+	// the first entry after the window logs the repeats first
+	now = now.Add(time.Hour)
+	fire(logrus.ErrorLevel, "db down", logrus.Fields{"component": "db", "attempt": 5})
+
+	hook.mu.Lock()
+	assert.Equal(t, []logrus.Fields{
+		{"component": "db", "attempt": 1},
+		{"component": "cache"},
+		{"component": "db"},
+		{"component": "db", "attempt": 4, repeatedField: 3},
+		{"component": "db", "attempt": 5},
+	}, hook.data)
+	hook.mu.Unlock()
+
+	fire(logrus.ErrorLevel, "db down", logrus.Fields{"component": "db", "attempt": 6})
+
+	require.NoError(t, h.Close())
+	require.NoError(t, h.Close(), "Expected closing twice to be fine")
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	assert.Equal(t, logrus.Fields{"component": "db", "attempt": 6, repeatedField: 1}, hook.data[len(hook.data)-1],
+		"Expected the repeats to be logged on close")
+}
+
+func TestDedupeHookPassesFatalThrough(t *testing.T) {
+	hook := newGatedHook()
+	close(hook.gate)
+
+	h := newDedupeHook(DedupeConfig{Window: time.Hour}, hook)
+	t.Cleanup(func() { _ = h.Close() })
+
+	for range 3 {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.ErrorLevel, Message: "db down"}))
+	}
+
+	for _, lvl := range []logrus.Level{logrus.FatalLevel, logrus.PanicLevel} {
+		for range 2 {
+			require.NoError(t, h.Fire(&logrus.Entry{Level: lvl, Message: lvl.String()}))
+		}
+	}
+
+	assert.Equal(t, []string{"db down", "db down", "fatal", "fatal", "panic", "panic"}, hook.getMessages(),
+		"Expected the held back errors to be logged before the first fatal")
+}
+
+func TestDedupeHookFlushesExpiredWindows(t *testing.T) {
+	hook := newGatedHook()
+	close(hook.gate)
+
+	h := newDedupeHook(DedupeConfig{Window: 10 * time.Millisecond}, hook)
+	t.Cleanup(func() { _ = h.Close() })
+
+	for range 5 {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.ErrorLevel, Message: "db down"}))
+	}
+
+	assert.Eventually(t, func() bool {
+		return len(hook.getMessages()) == 2
+	}, 5*time.Second, 10*time.Millisecond, "Expected the repeats to be logged once the window is over")
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	assert.Equal(t, logrus.Fields{repeatedField: 4}, hook.data[1])
+}
+
+func TestConfigureDedupe(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv(configKeyLogDedupeWindow, "1h")
+	t.Setenv(configKeyLogDedupeFields, "component")
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	logger, err := NewLogger(WithHooks(hook))
+	require.NoError(t, err)
+
+	require.Len(t, getConfiguredHooks(logger)[logrus.ErrorLevel], 1)
+
+	dedupe, ok := getConfiguredHooks(logger)[logrus.ErrorLevel][0].(*dedupeHook)
+	require.True(t, ok, "Expected the dedupe hook in front of the outputs")
+	assert.Equal(t, []string{"component"}, dedupe.fields)
+
+	logger.Error("db down")
+	logger.Error("db down")
+	assert.Equal(t, []string{"db down"}, hook.getMessages())
+
+	// reconfiguring logs the held back repeats
+	require.NoError(t, ConfigureLogger(logger, WithHooks(hook), WithDedupe(0)))
+	assert.Equal(t, []string{"db down", "db down"}, hook.getMessages())
+
+	_, err = NewLogger(WithDedupe(-time.Second))
+	require.EqualError(t, err, "failed to set log dedupe: -1s: invalid log dedupe window")
+}
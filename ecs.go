@@ -0,0 +1,112 @@
+package logrusconfigurator
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const ecsVersion = "1.6.0"
+
+const (
+	ecsKeyTimestamp        = "@timestamp"
+	ecsKeyLevel            = "log.level"
+	ecsKeyMessage          = "message"
+	ecsKeyVersion          = "ecs.version"
+	ecsKeyOriginFileName   = "log.origin.file.name"
+	ecsKeyOriginFileLine   = "log.origin.file.line"
+	ecsKeyOriginFunction   = "log.origin.function"
+	ecsKeyErrorMessage     = "error.message"
+	ecsKeyErrorType        = "error.type"
+	ecsKeyErrorStackTrace  = "error.stack_trace"
+	ecsReservedFieldPrefix = "fields."
+)
+
+// ecsFormatter formats entries as Elastic Common Schema JSON
+type ecsFormatter struct {
+	CallerPrettyfier func(*runtime.Frame) (string, string)
+}
+
+func (f *ecsFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(logrus.Fields, len(entry.Data)+8) //nolint:mnd
+
+	for key, value := range entry.Data {
+		if key == logrus.ErrorKey {
+			if err, ok := value.(error); ok {
+				addECSError(data, err)
+
+				continue
+			}
+		}
+
+		if isECSReservedKey(key) {
+			key = ecsReservedFieldPrefix + key
+		}
+
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		data[key] = value
+	}
+
+	data[ecsKeyTimestamp] = entry.Time.UTC().Format(time.RFC3339Nano)
+	data[ecsKeyLevel] = entry.Level.String()
+	data[ecsKeyMessage] = entry.Message
+	data[ecsKeyVersion] = ecsVersion
+
+	if entry.HasCaller() {
+		f.addCaller(data, entry.Caller)
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal fields to JSON")
+	}
+
+	return append(b, '\n'), nil
+}
+
+func (f *ecsFormatter) addCaller(data logrus.Fields, caller *runtime.Frame) {
+	funcName, fileName := caller.Function, caller.File
+	if f.CallerPrettyfier != nil {
+		funcName, fileName = f.CallerPrettyfier(caller)
+		// the prettyfier appends the line which has a field of its own in ECS
+		fileName = strings.TrimSuffix(fileName, fmt.Sprintf(":%d", caller.Line))
+	}
+
+	if funcName != "" {
+		data[ecsKeyOriginFunction] = funcName
+	}
+
+	if fileName != "" {
+		data[ecsKeyOriginFileName] = fileName
+		data[ecsKeyOriginFileLine] = caller.Line
+	}
+}
+
+func addECSError(data logrus.Fields, err error) {
+	data[ecsKeyErrorMessage] = err.Error()
+	data[ecsKeyErrorType] = fmt.Sprintf("%T", err)
+
+	var stackTracer interface{ StackTrace() errors.StackTrace }
+	if errors.As(err, &stackTracer) {
+		data[ecsKeyErrorStackTrace] = strings.TrimPrefix(fmt.Sprintf("%+v", stackTracer.StackTrace()), "\n")
+	}
+}
+
+// isECSReservedKey reports whether a field would clash
+// with one of the ECS fields set by the formatter
+func isECSReservedKey(key string) bool {
+	switch key {
+	case ecsKeyTimestamp, ecsKeyMessage, ecsKeyVersion:
+		return true
+	}
+
+	return strings.HasPrefix(key, "log.") || strings.HasPrefix(key, "error.")
+}
@@ -0,0 +1,146 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	stderrors "errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatECSEntry(t *testing.T, entry *logrus.Entry) map[string]any {
+	t.Helper()
+
+	formatter, err := getLogrusFormat(formatECS)
+	require.NoError(t, err)
+
+	out, err := formatter.Format(entry)
+	require.NoError(t, err)
+	require.Equal(t, byte('\n'), out[len(out)-1], "Expected a trailing newline")
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(out, &result))
+
+	return result
+}
+
+func TestECSFormatter(t *testing.T) {
+	entryTime := time.Date(2026, 1, 2, 3, 4, 5, 600, time.FixedZone("EET", 2*60*60))
+
+	result := formatECSEntry(t, &logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Time:    entryTime,
+		Level:   logrus.WarnLevel,
+		Message: "disk almost full",
+		Data: logrus.Fields{
+			"disk":      "/dev/sda1",
+			"message":   "clash",
+			"log.level": "clash",
+			"cause":     errors.New("nested error"),
+		},
+		Caller: &runtime.Frame{
+			Function: "github.com/example/package.TestFunction",
+			File:     "/path/to/file/test.go",
+			Line:     42,
+		},
+	})
+
+	assert.Equal(t, "2026-01-02T01:04:05.0000006Z", result["@timestamp"])
+	assert.Equal(t, "warning", result["log.level"])
+	assert.Equal(t, "disk almost full", result["message"])
+	assert.Equal(t, ecsVersion, result["ecs.version"])
+	assert.Equal(t, "github.com/example/package.TestFunction()", result["log.origin.function"])
+	assert.Equal(t, "test.go", result["log.origin.file.name"])
+	assert.InDelta(t, 42, result["log.origin.file.line"], 0)
+	assert.Equal(t, "/dev/sda1", result["disk"])
+	assert.Equal(t, "nested error", result["cause"])
+	assert.Equal(t, "clash", result["fields.message"])
+	assert.Equal(t, "clash", result["fields.log.level"])
+}
+
+func TestECSFormatterError(t *testing.T) {
+	t.Run("With stack trace", func(t *testing.T) {
+		result := formatECSEntry(t, &logrus.Entry{
+			Level:   logrus.ErrorLevel,
+			Message: "failed",
+			Data:    logrus.Fields{logrus.ErrorKey: errors.Wrap(errInvalidLogFormat, "wrapped")},
+		})
+
+		assert.Equal(t, "wrapped: invalid log format", result["error.message"])
+		assert.Equal(t, "*errors.withStack", result["error.type"])
+		assert.Contains(t, result["error.stack_trace"], "TestECSFormatterError")
+		assert.NotContains(t, result, "error")
+	})
+
+	t.Run("Without stack trace", func(t *testing.T) {
+		result := formatECSEntry(t, &logrus.Entry{
+			Level:   logrus.ErrorLevel,
+			Message: "failed",
+			Data:    logrus.Fields{logrus.ErrorKey: stderrors.New("plain")},
+		})
+
+		assert.Equal(t, "plain", result["error.message"])
+		assert.NotContains(t, result, "error.stack_trace")
+	})
+
+	t.Run("Non error value", func(t *testing.T) {
+		result := formatECSEntry(t, &logrus.Entry{
+			Level:   logrus.ErrorLevel,
+			Message: "failed",
+			Data:    logrus.Fields{logrus.ErrorKey: "just a string"},
+		})
+
+		assert.Equal(t, "just a string", result["error"])
+		assert.NotContains(t, result, "error.message")
+	})
+}
+
+func TestECSFormatterWithoutPrettyfier(t *testing.T) {
+	formatter := &ecsFormatter{}
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Level:   logrus.InfoLevel,
+		Message: "raw caller",
+		Caller:  &runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+	})
+	require.NoError(t, err)
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(out, &result))
+	assert.Equal(t, "main.main", result["log.origin.function"])
+	assert.Equal(t, "/src/main.go", result["log.origin.file.name"])
+}
+
+func TestECSFormatterMarshalError(t *testing.T) {
+	formatter := &ecsFormatter{}
+
+	_, err := formatter.Format(&logrus.Entry{
+		Level:   logrus.InfoLevel,
+		Message: "unmarshalable",
+		Data:    logrus.Fields{"ch": make(chan int)},
+	})
+	require.Error(t, err)
+}
+
+func TestECSFormatterThroughLogger(t *testing.T) {
+	unsetEnvs(t)
+
+	buffer := &bytes.Buffer{}
+
+	logger, err := NewLogger(WithFormat("ecs"), WithHooks(getStdoutHook(buffer)))
+	require.NoError(t, err)
+
+	logger.WithField("user", "jane").Info("logged in")
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(buffer.Bytes(), &result))
+	assert.Equal(t, "logged in", result["message"])
+	assert.Equal(t, "jane", result["user"])
+}
@@ -4,5 +4,20 @@ import "errors"
 
 var (
 	errInvalidLogLevel  = errors.New("invalid log level")
+	errInvalidLogLevels = errors.New("invalid per-package log level")
 	errInvalidLogFormat = errors.New("invalid log format")
+	errInvalidLogOutput = errors.New("invalid log output")
+	errInvalidLogSplit  = errors.New("invalid log split")
+	errInvalidLogColor  = errors.New("invalid log color")
+	errInvalidTTL       = errors.New("invalid ttl")
+
+	errInvalidConfigFile = errors.New("invalid log config file")
+	errInvalidLogAsync   = errors.New("invalid log async config")
+
+	errInvalidLogSampling  = errors.New("invalid log sampling rule")
+	errInvalidLogDedupe    = errors.New("invalid log dedupe window")
+	errInvalidLogRateLimit = errors.New("invalid log rate limit rule")
+	errInvalidLogRedact    = errors.New("invalid log redact config")
+
+	errOutputUnavailable = errors.New("log output unavailable, redialing later")
 )
@@ -0,0 +1,311 @@
+package logrusconfigurator
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	megabyte            = 1024 * 1024
+	backupTimeFormat    = "2006-01-02T15-04-05.000"
+	compressedExtension = ".gz"
+)
+
+// FileConfig configures the rotating log file
+type FileConfig struct {
+	// Path of the log file, empty disables the file output
+	Path string
+	// MaxSize in megabytes before the file gets rotated, 0 never rotates
+	MaxSize int
+	// MaxAge of the rotated files before they get removed, 0 keeps them
+	MaxAge time.Duration
+	// MaxBackups is the number of rotated files to keep, 0 keeps them all
+	MaxBackups int
+	// Compress gzips the rotated files
+	Compress bool
+}
+
+// rotatingFile is an io.WriteCloser that renames the log file
+// out of the way once it grows past maxSize and starts a new one.
+// Compressing and pruning the old files happens in the background.
+type rotatingFile struct {
+	mu         sync.Mutex
+	wg         sync.WaitGroup
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	compress   bool
+	file       *os.File
+	size       int64
+	closed     bool
+	now        func() time.Time
+}
+
+func newRotatingFile(cfg FileConfig) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:       cfg.Path,
+		maxSize:    int64(cfg.MaxSize) * megabyte,
+		maxAge:     cfg.MaxAge,
+		maxBackups: cfg.MaxBackups,
+		compress:   cfg.Compress,
+		now:        time.Now,
+	}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFileMode)
+	if err != nil {
+		return errors.Wrap(err, "failed to open log file")
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+
+		return errors.Wrap(err, "failed to stat log file")
+	}
+
+	f.file = file
+	f.size = info.Size()
+
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return 0, errors.Wrapf(os.ErrClosed, "log file %s", f.path)
+	}
+
+	// a failed rotation leaves no file open, the next write tries again
+	if f.file == nil {
+		if err := f.open(); err != nil {
+			return 0, err
+		}
+	}
+
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+
+	return n, errors.WithStack(err)
+}
+
+// rotate moves the current file to a timestamped backup and opens
+// a fresh one. The rename is atomic so no line ends up in both files.
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return errors.Wrap(err, "failed to close log file")
+	}
+
+	f.file = nil
+
+	now := f.now()
+
+	backup := f.nextBackupName(now)
+	if err := os.Rename(f.path, backup); err != nil {
+		return errors.Wrap(err, "failed to rotate log file")
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+
+	f.wg.Add(1)
+
+	go func() {
+		defer f.wg.Done()
+
+		f.cleanup(backup, now)
+	}()
+
+	return nil
+}
+
+// nextBackupName returns a backup name that isn't taken yet,
+// even when rotating more than once within the same millisecond
+func (f *rotatingFile) nextBackupName(t time.Time) string {
+	for {
+		backup := f.backupName(t)
+		if !fileExists(backup) && !fileExists(backup+compressedExtension) {
+			return backup
+		}
+
+		t = t.Add(time.Millisecond)
+	}
+}
+
+// backupName puts the UTC time in the name, which is what backups parses
+// it as, so the ages stay right whatever the time zone of the machine
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+
+	return strings.TrimSuffix(f.path, ext) + "-" + t.UTC().Format(backupTimeFormat) + ext
+}
+
+// cleanup compresses the freshly rotated backup and removes
+// the ones exceeding maxBackups or maxAge
+func (f *rotatingFile) cleanup(backup string, now time.Time) {
+	if f.compress {
+		// a failed compression leaves the plain backup behind,
+		// which is still better than losing it
+		_ = compressFile(backup)
+	}
+
+	f.prune(now)
+}
+
+func (f *rotatingFile) prune(now time.Time) {
+	if f.maxBackups <= 0 && f.maxAge <= 0 {
+		return
+	}
+
+	backups := f.backups()
+	cutoff := now.Add(-f.maxAge)
+
+	for i, backup := range backups {
+		tooMany := f.maxBackups > 0 && i >= f.maxBackups
+		tooOld := f.maxAge > 0 && backup.time.Before(cutoff)
+
+		if tooMany || tooOld {
+			_ = os.Remove(backup.path)
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	time time.Time
+}
+
+// backups lists the rotated files, newest first
+func (f *rotatingFile) backups() []backupFile {
+	dir := filepath.Dir(f.path)
+	ext := filepath.Ext(f.path)
+	prefix := strings.TrimSuffix(filepath.Base(f.path), ext) + "-"
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	backups := []backupFile{}
+
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), compressedExtension)
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+
+		t, err := time.Parse(backupTimeFormat, strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext))
+		if err != nil {
+			continue
+		}
+
+		backups = append(backups, backupFile{
+			path: filepath.Join(dir, entry.Name()),
+			time: t,
+		})
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].time.After(backups[j].time)
+	})
+
+	return backups
+}
+
+// Close closes the file and waits for the pending compressions
+// and removals to finish. Writing to it afterwards fails.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.wg.Wait()
+
+	f.closed = true
+
+	if f.file == nil {
+		return nil
+	}
+
+	err := f.file.Close()
+	f.file = nil
+
+	return errors.WithStack(err)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+
+	return err == nil
+}
+
+// compressFile gzips the file next to itself through a temporary
+// file and only removes the original once the rename succeeded
+func compressFile(path string) error {
+	src, err := os.Open(path) //nolint:gosec
+	if err != nil {
+		return errors.Wrap(err, "failed to open file")
+	}
+	defer src.Close()
+
+	tmpPath := path + compressedExtension + ".tmp"
+
+	dst, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, logFileMode)
+	if err != nil {
+		return errors.Wrap(err, "failed to create compressed file")
+	}
+
+	gz := gzip.NewWriter(dst)
+
+	if _, err := io.Copy(gz, src); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "failed to compress file")
+	}
+
+	if err := gz.Close(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "failed to compress file")
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "failed to close compressed file")
+	}
+
+	if err := os.Rename(tmpPath, path+compressedExtension); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return errors.Wrap(err, "failed to rename compressed file")
+	}
+
+	return errors.WithStack(os.Remove(path))
+}
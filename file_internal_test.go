@@ -0,0 +1,274 @@
+package logrusconfigurator
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRotatingFile(t *testing.T, maxSize int64) *rotatingFile {
+	t.Helper()
+
+	f, err := newRotatingFile(FileConfig{Path: filepath.Join(t.TempDir(), "app.log")})
+	require.NoError(t, err)
+
+	f.maxSize = maxSize
+
+	return f
+}
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	f := newTestRotatingFile(t, 10)
+
+	_, err := f.Write([]byte("0123456789"))
+	require.NoError(t, err)
+	assert.Empty(t, f.backups(), "No rotation expected at exactly max size")
+
+	_, err = f.Write([]byte("abc"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := f.backups()
+	require.Len(t, backups, 1, "Expected a single rotated file")
+
+	rotated, err := os.ReadFile(backups[0].path)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(rotated))
+
+	current, err := os.ReadFile(f.path)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(current))
+}
+
+func TestRotatingFileCompresses(t *testing.T) {
+	f := newTestRotatingFile(t, 5)
+	f.compress = true
+
+	_, err := f.Write([]byte("hello"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("world"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := f.backups()
+	require.Len(t, backups, 1)
+	require.True(t, strings.HasSuffix(backups[0].path, ".log.gz"), "Expected a gzipped backup")
+
+	file, err := os.Open(backups[0].path)
+	require.NoError(t, err)
+
+	defer file.Close()
+
+	gz, err := gzip.NewReader(file)
+	require.NoError(t, err)
+
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(content))
+}
+
+func TestRotatingFilePrunesBackups(t *testing.T) {
+	f := newTestRotatingFile(t, 1)
+	f.maxBackups = 2
+
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	f.now = func() time.Time {
+		now = now.Add(time.Second)
+
+		return now
+	}
+
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, f.Close())
+
+	backups := f.backups()
+	require.Len(t, backups, 2, "Expected only maxBackups rotated files")
+
+	newest, err := os.ReadFile(backups[0].path)
+	require.NoError(t, err)
+	assert.Equal(t, "d", string(newest))
+}
+
+func TestRotatingFilePrunesByAge(t *testing.T) {
+	f := newTestRotatingFile(t, 1)
+	f.maxAge = time.Hour
+
+	old := f.backupName(time.Now().Add(-2 * time.Hour))
+	require.NoError(t, os.WriteFile(old, []byte("old"), logFileMode))
+
+	_, err := f.Write([]byte("a"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("b"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := f.backups()
+	require.Len(t, backups, 1, "Expected the old backup to be removed")
+	assert.NotEqual(t, old, backups[0].path)
+}
+
+func TestRotatingFileBackupTimeZone(t *testing.T) {
+	f := newTestRotatingFile(t, 1)
+	f.maxAge = time.Hour
+
+	// behind UTC, a local time read as UTC would be hours too old
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	f.now = func() time.Time { return now }
+
+	_, err := f.Write([]byte("a"))
+	require.NoError(t, err)
+	_, err = f.Write([]byte("b"))
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	backups := f.backups()
+	require.Len(t, backups, 1, "Expected the fresh backup to be kept")
+	assert.True(t, now.Equal(backups[0].time), "Expected %s, got %s", now, backups[0].time)
+	assert.Equal(t, f.backupName(now.UTC()), backups[0].path)
+}
+
+func TestRotatingFileUniqueBackupNames(t *testing.T) {
+	f := newTestRotatingFile(t, 1)
+	f.now = func() time.Time {
+		return time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+
+	for _, line := range []string{"a", "b", "c"} {
+		_, err := f.Write([]byte(line))
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, f.Close())
+	assert.Len(t, f.backups(), 2, "Rotations at the same instant shouldn't overwrite each other")
+}
+
+func TestRotatingFileConcurrentWrites(t *testing.T) {
+	f := newTestRotatingFile(t, 64)
+	f.compress = true
+
+	const (
+		writers = 8
+		lines   = 50
+		line    = "0123456789\n"
+	)
+
+	var wg sync.WaitGroup
+
+	for range writers {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for range lines {
+				_, err := f.Write([]byte(line))
+				assert.NoError(t, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+	require.NoError(t, f.Close())
+
+	total := 0
+
+	for _, backup := range append(f.backups(), backupFile{path: f.path}) {
+		file, err := os.Open(backup.path)
+		require.NoError(t, err)
+
+		var r io.Reader = file
+		if strings.HasSuffix(backup.path, compressedExtension) {
+			r, err = gzip.NewReader(file)
+			require.NoError(t, err)
+		}
+
+		content, err := io.ReadAll(r)
+		require.NoError(t, err)
+		require.NoError(t, file.Close())
+
+		total += strings.Count(string(content), line)
+	}
+
+	assert.Equal(t, writers*lines, total, "Every line should end up in exactly one file")
+}
+
+func TestRotatingFileWriteAfterClose(t *testing.T) {
+	f := newTestRotatingFile(t, 0)
+
+	require.NoError(t, f.Close())
+	require.NoError(t, f.Close(), "Closing twice should be a no-op")
+
+	_, err := f.Write([]byte("again"))
+	require.ErrorIs(t, err, os.ErrClosed)
+
+	content, err := os.ReadFile(f.path)
+	require.NoError(t, err)
+	assert.Empty(t, content, "A closed file shouldn't get reopened")
+}
+
+func TestNewRotatingFileError(t *testing.T) {
+	_, err := newRotatingFile(FileConfig{Path: filepath.Join(t.TempDir(), "nope", "app.log")})
+	require.Error(t, err)
+}
+
+func TestCompressFileMissing(t *testing.T) {
+	require.Error(t, compressFile(filepath.Join(t.TempDir(), "nope.log")))
+}
+
+func TestConfigureWithFile(t *testing.T) {
+	unsetEnvs(t)
+
+	defer func() {
+		require.NoError(t, Configure())
+	}()
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(configKeyLogFile, logPath)
+	t.Setenv(configKeyLogFileMaxSize, "1")
+	t.Setenv(configKeyLogFileMaxAge, "24h")
+	t.Setenv(configKeyLogFileMaxBackups, "3")
+	t.Setenv(configKeyLogFileCompress, "true")
+
+	logger, err := NewLogger(WithFormat("json"))
+	require.NoError(t, err)
+
+	assert.Len(t, getLevelOutputHooks(logger, logrus.InfoLevel), 2, "Expected stdout and file hooks")
+	assert.Len(t, getLevelOutputHooks(logger, logrus.ErrorLevel), 2, "Expected stderr and file hooks")
+
+	logger.Info("to the rotating file")
+
+	loggerOutputsMu.Lock()
+	outputs := loggerOutputs[logger]
+	loggerOutputsMu.Unlock()
+
+	require.Len(t, outputs, 1)
+
+	file, ok := outputs[0].(*rotatingFile)
+	require.True(t, ok, "Expected a rotating file output")
+	assert.Equal(t, int64(megabyte), file.maxSize)
+	assert.Equal(t, 24*time.Hour, file.maxAge)
+	assert.Equal(t, 3, file.maxBackups)
+	assert.True(t, file.compress)
+
+	require.NoError(t, setLoggerOutputs(logger, nil))
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"to the rotating file"`)
+
+	_, err = NewLogger(WithFile(FileConfig{Path: filepath.Join(t.TempDir(), "nope", "app.log")}))
+	require.Error(t, err)
+}
@@ -12,18 +12,22 @@ import (
 type format string
 
 const (
-	formatJSON format = "json"
-	formatText format = "text"
+	formatJSON   format = "json"
+	formatText   format = "text"
+	formatLogfmt format = "logfmt"
+	formatECS    format = "ecs"
+	formatGELF   format = "gelf"
+	formatPretty format = "pretty"
 )
 
-func getLogrusFormat(format format) (logrus.Formatter, error) { //nolint:ireturn
-	callerPrettyfier := func(f *runtime.Frame) (string, string) {
-		filename := path.Base(f.File)
+func callerPrettyfier(f *runtime.Frame) (string, string) {
+	filename := path.Base(f.File)
 
-		return fmt.Sprintf("%s()", f.Function),
-			fmt.Sprintf("%s:%d", filename, f.Line)
-	}
+	return fmt.Sprintf("%s()", f.Function),
+		fmt.Sprintf("%s:%d", filename, f.Line)
+}
 
+func getLogrusFormat(format format) (logrus.Formatter, error) { //nolint:ireturn
 	switch format {
 	case formatJSON:
 		return &logrus.JSONFormatter{
@@ -33,18 +37,38 @@ func getLogrusFormat(format format) (logrus.Formatter, error) { //nolint:ireturn
 		return &logrus.TextFormatter{
 			CallerPrettyfier: callerPrettyfier,
 		}, nil
+	case formatLogfmt:
+		return &logfmtFormatter{
+			CallerPrettyfier: callerPrettyfier,
+		}, nil
+	case formatECS:
+		return &ecsFormatter{
+			CallerPrettyfier: callerPrettyfier,
+		}, nil
+	case formatGELF:
+		return newGELFFormatter(), nil
+	case formatPretty:
+		return newPrettyFormatter(), nil
 	default:
 		return nil, errors.Wrap(errInvalidLogFormat, string(format))
 	}
 }
 
-func setFormat(fmt format) error {
+func setLoggerFormat(logger *logrus.Logger, fmt format) error {
 	logrusFormatter, err := getLogrusFormat(fmt)
 	if err != nil {
 		return err
 	}
 
-	logrus.SetFormatter(logrusFormatter)
+	logger.SetFormatter(logrusFormatter)
+
+	if outputFormat, ok := getLoggerOutputFormat(logger); ok {
+		outputFormat.set(logrusFormatter, outputFormat.get().ReportCaller)
+	}
 
 	return nil
 }
+
+func setFormat(fmt format) error {
+	return setLoggerFormat(logrus.StandardLogger(), fmt)
+}
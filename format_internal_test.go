@@ -18,6 +18,10 @@ func TestGetLogrusFormat(t *testing.T) {
 	}{
 		{formatJSON, &logrus.JSONFormatter{}, false},
 		{formatText, &logrus.TextFormatter{}, false},
+		{formatLogfmt, &logfmtFormatter{}, false},
+		{formatECS, &ecsFormatter{}, false},
+		{formatGELF, &gelfFormatter{}, false},
+		{formatPretty, &prettyFormatter{}, false},
 		{"invalid", nil, true},
 	}
 
@@ -43,6 +47,10 @@ func TestSetFormat(t *testing.T) {
 	}{
 		{formatJSON, &logrus.JSONFormatter{}, false},
 		{formatText, &logrus.TextFormatter{}, false},
+		{formatLogfmt, &logfmtFormatter{}, false},
+		{formatECS, &ecsFormatter{}, false},
+		{formatGELF, &gelfFormatter{}, false},
+		{formatPretty, &prettyFormatter{}, false},
 		{"Invalid", nil, true},
 	}
 
@@ -160,3 +168,15 @@ func TestCallerPrettyfierFormatsCorrectly(t *testing.T) {
 		})
 	}
 }
+
+func TestSetLoggerFormat(t *testing.T) {
+	logger := logrus.New()
+	originalFormatter := logrus.StandardLogger().Formatter
+
+	require.NoError(t, setLoggerFormat(logger, formatJSON), "Unexpected error")
+	assert.IsType(t, &logrus.JSONFormatter{}, logger.Formatter, "Formatter type mismatch")
+	assert.Same(t, originalFormatter, logrus.StandardLogger().Formatter, "Standard logger should be untouched")
+
+	require.Error(t, setLoggerFormat(logger, "invalid"), "Expected error")
+	assert.IsType(t, &logrus.JSONFormatter{}, logger.Formatter, "Formatter should not change on error")
+}
@@ -0,0 +1,288 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const gelfVersion = "1.1"
+
+const (
+	// gelfChunkSize keeps the UDP datagrams below the usual WAN MTU
+	gelfChunkSize      = 1420
+	gelfChunkHeaderLen = 12
+	gelfMaxChunks      = 128
+)
+
+//nolint:gochecknoglobals
+var (
+	gelfChunkMagic        = []byte{0x1e, 0x0f}
+	gelfInvalidFieldChars = regexp.MustCompile(`[^\w.\-]`)
+)
+
+// gelfSeverities maps the logrus levels to syslog severities
+//
+//nolint:gochecknoglobals
+var gelfSeverities = map[logrus.Level]int{
+	logrus.PanicLevel: 1, // alert
+	logrus.FatalLevel: 2, // critical
+	logrus.ErrorLevel: 3, // error
+	logrus.WarnLevel:  4, // warning
+	logrus.InfoLevel:  6, // informational
+	logrus.DebugLevel: 7, // debug
+	logrus.TraceLevel: 7, // debug
+}
+
+// gelfFormatter formats entries as GELF 1.1 payloads
+type gelfFormatter struct {
+	Host             string
+	CallerPrettyfier func(*runtime.Frame) (string, string)
+}
+
+func newGELFFormatter() *gelfFormatter {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+
+	return &gelfFormatter{
+		Host:             host,
+		CallerPrettyfier: callerPrettyfier,
+	}
+}
+
+func (f *gelfFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	data := make(map[string]any, len(entry.Data)+8) //nolint:mnd
+
+	for key, value := range entry.Data {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+
+		data[gelfFieldKey(key)] = value
+	}
+
+	shortMessage, _, multiline := strings.Cut(entry.Message, "\n")
+	if multiline {
+		data["full_message"] = entry.Message
+	}
+
+	data["version"] = gelfVersion
+	data["host"] = f.Host
+	data["short_message"] = shortMessage
+	data["timestamp"] = float64(entry.Time.UnixMilli()) / 1000 //nolint:mnd
+	data["level"] = gelfSeverities[entry.Level]
+
+	if entry.HasCaller() {
+		funcName, fileName := entry.Caller.Function, fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		if f.CallerPrettyfier != nil {
+			funcName, fileName = f.CallerPrettyfier(entry.Caller)
+		}
+
+		data["_function"] = funcName
+		data["_file"] = fileName
+	}
+
+	b, err := json.Marshal(data)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal fields to JSON")
+	}
+
+	return append(b, '\n'), nil
+}
+
+// gelfFieldKey turns a field into a valid GELF additional field
+// which has to be prefixed with an underscore and can't be _id
+func gelfFieldKey(key string) string {
+	key = gelfInvalidFieldChars.ReplaceAllString(key, "_")
+	if key == "id" {
+		key = "fields.id"
+	}
+
+	return "_" + key
+}
+
+// gelfHook sends every entry as a GELF payload no matter which
+// format the logger uses, so it can be added next to the console output
+type gelfHook struct {
+	formatter *gelfFormatter
+	writer    gelfWriter
+	// format decides on the caller reporting once it's bound
+	format *outputFormat
+}
+
+type gelfWriter interface {
+	writeMessage(payload []byte) error
+	Close() error
+}
+
+func newGELFHook(network string, address string) (*gelfHook, error) {
+	var (
+		w   gelfWriter
+		err error
+	)
+
+	switch network {
+	case "udp":
+		w, err = newGELFUDPWriter(address)
+	case "tcp":
+		w, err = newGELFTCPWriter(address)
+	default:
+		return nil, errors.Wrap(errInvalidLogOutput, network)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &gelfHook{
+		formatter: newGELFFormatter(),
+		writer:    w,
+	}, nil
+}
+
+func (h *gelfHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *gelfHook) Fire(entry *logrus.Entry) error {
+	if h.format != nil {
+		entry = h.format.entry(entry)
+	}
+
+	payload, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+
+	return h.writer.writeMessage(bytes.TrimSuffix(payload, []byte("\n")))
+}
+
+func (h *gelfHook) Close() error {
+	return h.writer.Close()
+}
+
+// gelfUDPWriter gzips the payloads and splits the ones
+// that don't fit in a single datagram into GELF chunks
+type gelfUDPWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func newGELFUDPWriter(address string) (*gelfUDPWriter, error) {
+	dialer := &net.Dialer{Timeout: netDialTimeout}
+
+	conn, err := dialer.DialContext(context.Background(), "udp", address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial udp %s", address)
+	}
+
+	return &gelfUDPWriter{conn: conn}, nil
+}
+
+func (w *gelfUDPWriter) writeMessage(payload []byte) error {
+	compressed := &bytes.Buffer{}
+	gz := gzip.NewWriter(compressed)
+
+	if _, err := gz.Write(payload); err != nil {
+		return errors.Wrap(err, "failed to compress GELF payload")
+	}
+
+	if err := gz.Close(); err != nil {
+		return errors.Wrap(err, "failed to compress GELF payload")
+	}
+
+	chunks, err := gelfChunks(compressed.Bytes())
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, chunk := range chunks {
+		if _, err := w.conn.Write(chunk); err != nil {
+			return errors.Wrap(err, "failed to send GELF chunk")
+		}
+	}
+
+	return nil
+}
+
+func (w *gelfUDPWriter) Close() error {
+	return errors.WithStack(w.conn.Close())
+}
+
+// gelfChunks returns the payload as is when it fits in a single
+// datagram, otherwise splits it up in chunks sharing a random message id
+func gelfChunks(payload []byte) ([][]byte, error) {
+	if len(payload) <= gelfChunkSize {
+		return [][]byte{payload}, nil
+	}
+
+	dataSize := gelfChunkSize - gelfChunkHeaderLen
+	count := (len(payload) + dataSize - 1) / dataSize
+
+	if count > gelfMaxChunks {
+		return nil, errors.Errorf("GELF payload too big: %d chunks needed, %d allowed", count, gelfMaxChunks)
+	}
+
+	messageID := make([]byte, 8) //nolint:mnd
+	if _, err := rand.Read(messageID); err != nil {
+		return nil, errors.Wrap(err, "failed to generate GELF message id")
+	}
+
+	chunks := make([][]byte, 0, count)
+
+	for i := range count {
+		end := min((i+1)*dataSize, len(payload))
+
+		chunk := make([]byte, 0, gelfChunkHeaderLen+end-i*dataSize)
+		chunk = append(chunk, gelfChunkMagic...)
+		chunk = append(chunk, messageID...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, payload[i*dataSize:end]...)
+
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks, nil
+}
+
+// gelfTCPWriter sends null delimited payloads, GELF over TCP
+// doesn't support compression
+type gelfTCPWriter struct {
+	w *netWriter
+}
+
+func newGELFTCPWriter(address string) (*gelfTCPWriter, error) {
+	w, err := newNetWriter("tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gelfTCPWriter{w: w}, nil
+}
+
+func (w *gelfTCPWriter) writeMessage(payload []byte) error {
+	_, err := w.w.Write(append(payload, 0))
+
+	return err
+}
+
+func (w *gelfTCPWriter) Close() error {
+	return w.w.Close()
+}
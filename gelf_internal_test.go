@@ -0,0 +1,252 @@
+package logrusconfigurator
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGELFFormatter(t *testing.T) {
+	formatter := newGELFFormatter()
+	formatter.Host = "test-host"
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Time:    time.Date(2026, 1, 2, 3, 4, 5, 678_000_000, time.UTC),
+		Level:   logrus.WarnLevel,
+		Message: "first line\nsecond line",
+		Data: logrus.Fields{
+			"user":          "jane",
+			"id":            42,
+			"bad key!":      true,
+			logrus.ErrorKey: errors.New("it broke"),
+		},
+		Caller: &runtime.Frame{
+			Function: "github.com/example/package.TestFunction",
+			File:     "/path/to/file/test.go",
+			Line:     42,
+		},
+	})
+	require.NoError(t, err)
+	require.True(t, bytes.HasSuffix(out, []byte("\n")), "Expected a trailing newline")
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(out, &result))
+
+	assert.Equal(t, "1.1", result["version"])
+	assert.Equal(t, "test-host", result["host"])
+	assert.Equal(t, "first line", result["short_message"])
+	assert.Equal(t, "first line\nsecond line", result["full_message"])
+	assert.InDelta(t, 1767323045.678, result["timestamp"], 0.0001)
+	assert.InDelta(t, 4, result["level"], 0)
+	assert.Equal(t, "jane", result["_user"])
+	assert.InDelta(t, 42, result["_fields.id"], 0)
+	assert.Equal(t, true, result["_bad_key_"])
+	assert.Equal(t, "it broke", result["_error"])
+	assert.Equal(t, "github.com/example/package.TestFunction()", result["_function"])
+	assert.Equal(t, "test.go:42", result["_file"])
+	assert.NotContains(t, result, "_id")
+}
+
+func TestGELFFormatterSingleLine(t *testing.T) {
+	formatter := &gelfFormatter{Host: "h"}
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Level:   logrus.InfoLevel,
+		Message: "single",
+		Caller:  &runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+	})
+	require.NoError(t, err)
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(out, &result))
+	assert.NotContains(t, result, "full_message")
+	assert.Equal(t, "main.main", result["_function"])
+	assert.Equal(t, "/src/main.go:7", result["_file"])
+
+	_, err = formatter.Format(&logrus.Entry{Data: logrus.Fields{"ch": make(chan int)}})
+	require.Error(t, err)
+}
+
+func TestGELFSeverities(t *testing.T) {
+	testCases := []struct {
+		level    logrus.Level
+		expected int
+	}{
+		{logrus.PanicLevel, 1},
+		{logrus.FatalLevel, 2},
+		{logrus.ErrorLevel, 3},
+		{logrus.WarnLevel, 4},
+		{logrus.InfoLevel, 6},
+		{logrus.DebugLevel, 7},
+		{logrus.TraceLevel, 7},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, gelfSeverities[tc.level])
+		})
+	}
+}
+
+func TestGELFChunks(t *testing.T) {
+	small := []byte("small")
+
+	chunks, err := gelfChunks(small)
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{small}, chunks)
+
+	payload := bytes.Repeat([]byte("x"), gelfChunkSize*3)
+
+	chunks, err = gelfChunks(payload)
+	require.NoError(t, err)
+	require.Len(t, chunks, 4)
+
+	reassembled := []byte{}
+
+	for i, chunk := range chunks {
+		assert.LessOrEqual(t, len(chunk), gelfChunkSize)
+		assert.Equal(t, gelfChunkMagic, chunk[:2])
+		assert.Equal(t, chunks[0][2:10], chunk[2:10], "Chunks should share the message id")
+		assert.Equal(t, byte(i), chunk[10])
+		assert.Equal(t, byte(4), chunk[11])
+
+		reassembled = append(reassembled, chunk[gelfChunkHeaderLen:]...)
+	}
+
+	assert.Equal(t, payload, reassembled)
+
+	_, err = gelfChunks(make([]byte, gelfChunkSize*gelfMaxChunks))
+	require.Error(t, err, "Expected error for a payload needing too many chunks")
+}
+
+func readGELFDatagram(t *testing.T, conn net.PacketConn) map[string]any {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+
+	compressed := []byte{}
+	buf := make([]byte, 65535)
+
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		require.NoError(t, err)
+
+		datagram := buf[:n]
+		if !bytes.HasPrefix(datagram, gelfChunkMagic) {
+			compressed = append(compressed, datagram...)
+
+			break
+		}
+
+		compressed = append(compressed, datagram[gelfChunkHeaderLen:]...)
+		if datagram[10] == datagram[11]-1 {
+			break
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+
+	payload, err := io.ReadAll(gz)
+	require.NoError(t, err)
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(payload, &result))
+
+	return result
+}
+
+func TestGELFUDPOutput(t *testing.T) {
+	unsetEnvs(t)
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	logger, err := NewLogger(WithOutputs("gelf+udp://" + conn.LocalAddr().String()))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, setLoggerOutputs(logger, nil))
+	}()
+
+	logger.WithField("user", "jane").Error("over udp")
+
+	result := readGELFDatagram(t, conn)
+	assert.Equal(t, "over udp", result["short_message"])
+	assert.Equal(t, "jane", result["_user"])
+	assert.InDelta(t, 3, result["level"], 0)
+
+	// random data doesn't compress well so this needs chunking
+	big := make([]byte, gelfChunkSize*4)
+	_, err = rand.Read(big)
+	require.NoError(t, err)
+
+	for i := range big {
+		big[i] = big[i]%94 + '!'
+	}
+
+	logger.Info(string(big))
+
+	result = readGELFDatagram(t, conn)
+	assert.Equal(t, string(big), result["short_message"])
+}
+
+func TestGELFTCPOutput(t *testing.T) {
+	unsetEnvs(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	logger, err := NewLogger(WithOutputs("gelf+tcp://"+listener.Addr().String()), WithLevel("debug"))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, setLoggerOutputs(logger, nil))
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	logger.Debug("first")
+	logger.Warn("second")
+
+	reader := bufio.NewReader(conn)
+
+	for _, expected := range []string{"logrus-configurator: level: debug", "first", "second"} {
+		message, err := reader.ReadString(0)
+		require.NoError(t, err)
+
+		result := map[string]any{}
+		require.NoError(t, json.Unmarshal([]byte(strings.TrimSuffix(message, "\x00")), &result))
+		assert.Contains(t, result["short_message"], expected)
+	}
+}
+
+func TestGELFOutputErrors(t *testing.T) {
+	_, _, err := getOutputHook("gelf+tcp://127.0.0.1:1", defaultStderrLevels)
+	require.Error(t, err)
+
+	_, err = newGELFHook("sctp", "127.0.0.1:12201")
+	require.ErrorIs(t, err, errInvalidLogOutput)
+}
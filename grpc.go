@@ -0,0 +1,135 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// grpcCodes are the names of the gRPC status codes
+//
+//nolint:gochecknoglobals
+var grpcCodes = []string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// grpcCodeLevels are the levels of the gRPC status codes, the errors
+// the caller is to blame for are info, the ones worth a look are warn
+// and the ones the service is to blame for are error
+//
+//nolint:gochecknoglobals
+var grpcCodeLevels = []logrus.Level{
+	logrus.InfoLevel,  // OK
+	logrus.InfoLevel,  // Canceled
+	logrus.ErrorLevel, // Unknown
+	logrus.InfoLevel,  // InvalidArgument
+	logrus.WarnLevel,  // DeadlineExceeded
+	logrus.InfoLevel,  // NotFound
+	logrus.InfoLevel,  // AlreadyExists
+	logrus.WarnLevel,  // PermissionDenied
+	logrus.WarnLevel,  // ResourceExhausted
+	logrus.WarnLevel,  // FailedPrecondition
+	logrus.WarnLevel,  // Aborted
+	logrus.WarnLevel,  // OutOfRange
+	logrus.ErrorLevel, // Unimplemented
+	logrus.ErrorLevel, // Internal
+	logrus.WarnLevel,  // Unavailable
+	logrus.ErrorLevel, // DataLoss
+	logrus.InfoLevel,  // Unauthenticated
+}
+
+// GRPCCall describes a finished gRPC call
+type GRPCCall struct {
+	// Method is the full method name, e.g. /pkg.Service/Method
+	Method string
+	// Code is the gRPC status code, status.Code(err)
+	Code uint32
+	// Client tells a call made from one received
+	Client   bool
+	Duration time.Duration
+	// Peer is the address of the other side
+	Peer     string
+	Metadata map[string][]string
+	Err      error
+}
+
+// GRPCLogger logs finished gRPC calls through the logger, or the
+// standard one when it's nil, at the level of their status code.
+// It doesn't depend on grpc-go, the interceptors in the logrusgrpc
+// module pass the calls on.
+type GRPCLogger struct {
+	Logger *logrus.Logger
+	// Metadata lists the metadata keys that get logged, nothing
+	// gets logged by default as it's likely to hold credentials
+	Metadata []string
+}
+
+// Log logs the call with the fields of the entry in the context, if
+// there's one. The entry's logger only gets used when Logger is nil.
+func (l *GRPCLogger) Log(ctx context.Context, call GRPCCall) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	entry, ok := ctx.Value(entryContextKey{}).(*logrus.Entry)
+
+	switch {
+	case !ok && l.Logger == nil:
+		entry = logrus.NewEntry(logrus.StandardLogger())
+	case !ok:
+		entry = logrus.NewEntry(l.Logger)
+	case l.Logger != nil && entry.Logger != l.Logger:
+		entry = l.Logger.WithFields(entry.Data)
+	}
+
+	fields := logrus.Fields{
+		"grpc.method": call.Method,
+		"grpc.code":   getGRPCCodeName(call.Code),
+		"duration_ms": float64(call.Duration) / float64(time.Millisecond),
+	}
+
+	if call.Peer != "" {
+		fields["grpc.peer"] = call.Peer
+	}
+
+	for _, key := range l.Metadata {
+		key = strings.ToLower(key)
+		if values, ok := call.Metadata[key]; ok {
+			fields["grpc.metadata."+key] = strings.Join(values, ",")
+		}
+	}
+
+	entry = entry.WithContext(ctx).WithFields(fields)
+	if call.Err != nil {
+		entry = entry.WithError(call.Err)
+	}
+
+	msg := "grpc server call"
+	if call.Client {
+		msg = "grpc client call"
+	}
+
+	entry.Log(getGRPCCodeLevel(call.Code), msg)
+}
+
+func getGRPCCodeName(code uint32) string {
+	if int(code) < len(grpcCodes) {
+		return grpcCodes[code]
+	}
+
+	return "Code(" + strconv.FormatUint(uint64(code), 10) + ")"
+}
+
+func getGRPCCodeLevel(code uint32) logrus.Level {
+	if int(code) < len(grpcCodeLevels) {
+		return grpcCodeLevels[code]
+	}
+
+	return logrus.ErrorLevel
+}
@@ -0,0 +1,129 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGRPCLogger(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(WithFormat("json"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	l := &GRPCLogger{Logger: logger, Metadata: []string{"X-Tenant", "user-agent"}}
+
+	testCases := []struct {
+		name     string
+		call     GRPCCall
+		expected map[string]any
+	}{
+		{
+			name: "Server OK",
+			call: GRPCCall{
+				Method:   "/orders.Orders/Create",
+				Duration: 1500 * time.Microsecond,
+				Peer:     "10.0.0.1:5000",
+				Metadata: map[string][]string{
+					"x-tenant":      {"acme"},
+					"authorization": {"Bearer secret"},
+				},
+			},
+			expected: map[string]any{
+				"level":                  "info",
+				"msg":                    "grpc server call",
+				"grpc.method":            "/orders.Orders/Create",
+				"grpc.code":              "OK",
+				"grpc.peer":              "10.0.0.1:5000",
+				"grpc.metadata.x-tenant": "acme",
+				"duration_ms":            1.5,
+			},
+		},
+		{
+			name: "Client unavailable",
+			call: GRPCCall{
+				Method: "/orders.Orders/Get",
+				Code:   14,
+				Client: true,
+				Err:    errors.New("connection refused"),
+			},
+			expected: map[string]any{
+				"level":         "warning",
+				"msg":           "grpc client call",
+				"grpc.method":   "/orders.Orders/Get",
+				"grpc.code":     "Unavailable",
+				"duration_ms":   float64(0),
+				logrus.ErrorKey: "connection refused",
+			},
+		},
+		{
+			name: "Internal",
+			call: GRPCCall{Method: "/orders.Orders/Get", Code: 13},
+			expected: map[string]any{
+				"level":       "error",
+				"msg":         "grpc server call",
+				"grpc.method": "/orders.Orders/Get",
+				"grpc.code":   "Internal",
+				"duration_ms": float64(0),
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			l.Log(t.Context(), tc.call)
+
+			result := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+			delete(result, "time")
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestGRPCLoggerContextEntry(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	ctx := WithContext(t.Context(), logger.WithField(requestIDField, "abc"))
+
+	(&GRPCLogger{}).Log(ctx, GRPCCall{Method: "/orders.Orders/Get"})
+	assert.Contains(t, buf.String(), "request_id=abc")
+
+	// the configured logger wins over the one of the entry
+	var other bytes.Buffer
+
+	otherLogger, err := NewLogger(WithHooks(getAllLevelsHook(&other)))
+	require.NoError(t, err)
+
+	buf.Reset()
+	(&GRPCLogger{Logger: otherLogger}).Log(ctx, GRPCCall{Method: "/orders.Orders/Get"})
+	assert.Empty(t, buf.String())
+	assert.Contains(t, other.String(), "request_id=abc")
+
+	other.Reset()
+	(&GRPCLogger{Logger: otherLogger}).Log(nil, GRPCCall{Method: "/orders.Orders/Get"}) //nolint:staticcheck
+	assert.Contains(t, other.String(), "grpc.method=/orders.Orders/Get")
+}
+
+func TestGetGRPCCode(t *testing.T) {
+	assert.Equal(t, "NotFound", getGRPCCodeName(5))
+	assert.Equal(t, logrus.InfoLevel, getGRPCCodeLevel(5))
+	assert.Equal(t, "Unauthenticated", getGRPCCodeName(16))
+	assert.Equal(t, "Code(42)", getGRPCCodeName(42))
+	assert.Equal(t, logrus.ErrorLevel, getGRPCCodeLevel(42))
+}
@@ -3,52 +3,153 @@ package logrusconfigurator
 import (
 	"io"
 	"os"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/writer"
 )
 
+// loggerFormats keeps the output format of each logger
+// so the admin handler can change it in place
+//
+//nolint:gochecknoglobals
+var (
+	loggerFormatsMu sync.RWMutex
+	loggerFormats   = map[*logrus.Logger]*outputFormat{}
+)
+
 func getStderrHook(w io.Writer) logrus.Hook { //nolint:ireturn
+	return getStderrLevelsHook(w, defaultStderrLevels)
+}
+
+func getStdoutHook(w io.Writer) logrus.Hook { //nolint:ireturn
+	return getStdoutLevelsHook(w, defaultStderrLevels)
+}
+
+func getStderrLevelsHook(w io.Writer, stderrLevels []logrus.Level) logrus.Hook { //nolint:ireturn
 	if w == nil {
 		w = os.Stderr
 	}
 
 	return &writer.Hook{
-		Writer: w,
-		LogLevels: []logrus.Level{
-			logrus.PanicLevel,
-			logrus.FatalLevel,
-			logrus.ErrorLevel,
-			logrus.WarnLevel,
-		},
+		Writer:    w,
+		LogLevels: stderrLevels,
 	}
 }
 
-func getStdoutHook(w io.Writer) logrus.Hook { //nolint:ireturn
+func getStdoutLevelsHook(w io.Writer, stderrLevels []logrus.Level) logrus.Hook { //nolint:ireturn
 	if w == nil {
 		w = os.Stdout
 	}
 
 	return &writer.Hook{
-		Writer: w,
-		LogLevels: []logrus.Level{
-			logrus.InfoLevel,
-			logrus.DebugLevel,
-			logrus.TraceLevel,
-		},
+		Writer:    w,
+		LogLevels: getStdoutLevels(stderrLevels),
 	}
 }
 
-func clearLoggerHooks(logger *logrus.Logger) {
-	logger.Hooks = make(logrus.LevelHooks)
+func getAllLevelsHook(w io.Writer) logrus.Hook { //nolint:ireturn
+	return &writer.Hook{
+		Writer:    w,
+		LogLevels: logrus.AllLevels,
+	}
+}
+
+// outputFormat is the formatter and the caller reporting the output
+// hooks of a config format the entries with. It's stored in a logger of
+// its own as that's where the formatters look for the caller reporting.
+type outputFormat struct {
+	logger atomic.Pointer[logrus.Logger]
+}
+
+func newOutputFormat(formatter logrus.Formatter, reportCaller bool) *outputFormat {
+	f := &outputFormat{}
+	f.set(formatter, reportCaller)
+
+	return f
+}
+
+func (f *outputFormat) set(formatter logrus.Formatter, reportCaller bool) {
+	f.logger.Store(&logrus.Logger{
+		Out:          io.Discard,
+		Formatter:    formatter,
+		ReportCaller: reportCaller,
+		Level:        logrus.TraceLevel,
+	})
+}
+
+func (f *outputFormat) get() *logrus.Logger {
+	return f.logger.Load()
+}
+
+// entry returns a copy of the entry to be formatted with the output format
+func (f *outputFormat) entry(entry *logrus.Entry) *logrus.Entry {
+	formatted := *entry
+	formatted.Logger = f.get()
+
+	return &formatted
+}
+
+// outputHook writes with the output format of the config it was built
+// for instead of reading the formatter off the logger, so a new format
+// goes live together with the hooks while entries are being logged
+type outputHook struct {
+	*writer.Hook
+	format *outputFormat
+}
+
+func (h *outputHook) Fire(entry *logrus.Entry) error {
+	formatted := h.format.entry(entry)
+
+	line, err := formatted.Logger.Formatter.Format(formatted)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	_, err = h.Writer.Write(line)
+
+	return err //nolint:wrapcheck
+}
+
+// bindOutputFormat makes the writer and GELF hooks use the output format
+func bindOutputFormat(hooks []logrus.Hook, format *outputFormat) []logrus.Hook {
+	bound := make([]logrus.Hook, 0, len(hooks))
+
+	for _, hook := range hooks {
+		switch h := hook.(type) {
+		case *writer.Hook:
+			hook = &outputHook{Hook: h, format: format}
+		case *outputHook:
+			hook = &outputHook{Hook: h.Hook, format: format}
+		case *gelfHook:
+			h.format = format
+		}
+
+		bound = append(bound, hook)
+	}
+
+	return bound
+}
+
+func setLoggerOutputFormat(logger *logrus.Logger, format *outputFormat) {
+	loggerFormatsMu.Lock()
+	defer loggerFormatsMu.Unlock()
+
+	loggerFormats[logger] = format
+}
+
+func getLoggerOutputFormat(logger *logrus.Logger) (*outputFormat, bool) {
+	loggerFormatsMu.RLock()
+	defer loggerFormatsMu.RUnlock()
+
+	format, ok := loggerFormats[logger]
+
+	return format, ok
 }
 
-func addLoggerDefaultHooks(logger *logrus.Logger) {
-	addLoggerHooks(
-		logger,
-		getStderrHook(nil),
-		getStdoutHook(nil),
-	)
+func clearLoggerHooks(logger *logrus.Logger) {
+	logger.ReplaceHooks(make(logrus.LevelHooks))
 }
 
 func addLoggerHooks(logger *logrus.Logger, hooks ...logrus.Hook) {
@@ -57,21 +158,47 @@ func addLoggerHooks(logger *logrus.Logger, hooks ...logrus.Hook) {
 	}
 }
 
+// addLoggerHook adds the hook behind the per-package levels, if
+// there are any, so it doesn't get the entries they drop
 func addLoggerHook(logger *logrus.Logger, hook logrus.Hook) {
+	if levels := getLoggerLevels(logger); levels != nil {
+		levels.addHook(hook)
+
+		return
+	}
+
 	logger.AddHook(hook)
 }
 
+// setLoggerHooks swaps all of the hooks at once
+// so no entry gets logged with only some of them
 func setLoggerHooks(logger *logrus.Logger, hooks ...logrus.Hook) {
-	clearLoggerHooks(logger)
-	addLoggerHooks(logger, hooks...)
+	levelHooks := make(logrus.LevelHooks)
+	for _, hook := range hooks {
+		levelHooks.Add(hook)
+	}
+
+	logger.ReplaceHooks(levelHooks)
 }
 
-// SetHooks sets custom hooks for the standard logger, replacing any existing hooks
+// SetHooks sets custom hooks for the standard logger, replacing any existing
+// hooks. With per-package levels they replace the ones behind the levels so
+// they only get the entries that aren't dropped.
 func SetHooks(hooks ...logrus.Hook) {
-	setLoggerHooks(logrus.StandardLogger(), hooks...)
+	logger := logrus.StandardLogger()
+
+	if levels := getLoggerLevels(logger); levels != nil {
+		levels.setHooks(hooks...)
+
+		return
+	}
+
+	setLoggerHooks(logger, hooks...)
 }
 
-// AddHook adds a single hook to the standard logger
+// AddHook adds a single hook to the standard logger, behind the per-package
+// levels if there are any. Hooks added with logger.AddHook itself fire before
+// the levels get checked, so they get every entry up to the most verbose one.
 func AddHook(hook logrus.Hook) {
 	addLoggerHook(logrus.StandardLogger(), hook)
 }
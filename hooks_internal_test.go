@@ -2,7 +2,12 @@ package logrusconfigurator
 
 import (
 	"bytes"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/sirupsen/logrus/hooks/writer"
@@ -125,4 +130,77 @@ func TestGetStdoutHookWithCustomWriter(t *testing.T) {
 		logrus.TraceLevel,
 	}
 	assert.Equal(t, expectedLevels, writerHook.LogLevels, "Log levels should match expected stdout levels")
-}
\ No newline at end of file
+}
+
+func TestOutputHookUsesItsOwnFormat(t *testing.T) {
+	unsetEnvs(t)
+
+	buffer := &bytes.Buffer{}
+
+	logger, err := NewLogger(WithFormat("json"), WithHooks(getAllLevelsHook(buffer)))
+	require.NoError(t, err)
+
+	// changing the logger's formatter behind the config's back
+	// doesn't reach the output hooks
+	logger.SetFormatter(&logrus.TextFormatter{})
+	logger.Info("bound")
+
+	assert.Contains(t, buffer.String(), `"msg":"bound"`)
+}
+
+func TestReconfigureWhileLogging(t *testing.T) {
+	unsetEnvs(t)
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	opts := []Option{WithLevel("info"), WithOutputs("file://" + logPath)}
+
+	logger, err := NewLogger(opts...)
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+
+	var (
+		wg     sync.WaitGroup
+		logged atomic.Int64
+	)
+
+	waitForLogging := func() {
+		start := logged.Load()
+
+		require.Eventually(t, func() bool {
+			return logged.Load() > start+100
+		}, 5*time.Second, time.Millisecond)
+	}
+
+	for range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-done:
+					return
+				default:
+					logger.WithField("key", "value").Info("busy")
+					logged.Add(1)
+				}
+			}
+		}()
+	}
+
+	for i, format := range []string{"json", "text", "logfmt", "ecs", "pretty", "json"} {
+		waitForLogging()
+		require.NoError(t, ConfigureLogger(logger, append(opts, WithFormat(format), WithReportCaller(i%2 == 0))...))
+	}
+
+	waitForLogging()
+
+	close(done)
+	wg.Wait()
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "busy")
+}
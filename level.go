@@ -28,13 +28,41 @@ func getLogrusLevel(lvl level) (logrus.Level, error) {
 	return parsedLevel, nil
 }
 
-func setLevel(lvl level) error {
+func setLoggerLevel(logger *logrus.Logger, lvl level) error {
 	logrusLevel, err := getLogrusLevel(lvl)
 	if err != nil {
 		return err
 	}
 
-	logrus.SetLevel(logrusLevel)
+	setLoggerBaseLevel(logger, logrusLevel)
 
 	return nil
 }
+
+// setLoggerBaseLevel sets the level of the entries no per-package
+// level applies to. The logger itself gets the most verbose level
+// of all so the per-package levels still get their entries.
+func setLoggerBaseLevel(logger *logrus.Logger, lvl logrus.Level) {
+	hook := getLoggerLevels(logger)
+	if hook == nil {
+		logger.SetLevel(lvl)
+
+		return
+	}
+
+	hook.base.Store(uint32(lvl))
+	logger.SetLevel(max(lvl, hook.loudest))
+}
+
+// getLoggerBaseLevel returns the level set by setLoggerBaseLevel
+func getLoggerBaseLevel(logger *logrus.Logger) logrus.Level {
+	if hook := getLoggerLevels(logger); hook != nil {
+		return hook.baseLevel()
+	}
+
+	return logger.GetLevel()
+}
+
+func setLevel(lvl level) error {
+	return setLoggerLevel(logrus.StandardLogger(), lvl)
+}
@@ -64,3 +64,15 @@ func TestSetLevel(t *testing.T) {
 		}
 	}
 }
+
+func TestSetLoggerLevel(t *testing.T) {
+	logger := logrus.New()
+	originalLevel := logrus.GetLevel()
+
+	require.NoError(t, setLoggerLevel(logger, levelTrace), "Unexpected error")
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel(), "Log level mismatch")
+	assert.Equal(t, originalLevel, logrus.GetLevel(), "Standard logger should be untouched")
+
+	require.Error(t, setLoggerLevel(logger, "invalid"), "Expected error")
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel(), "Log level should not change on error")
+}
@@ -0,0 +1,319 @@
+package logrusconfigurator
+
+import (
+	"io"
+	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// componentField picks the per-package level by name instead of by caller
+	componentField = "component"
+
+	levelRuleWildcard = "/*"
+)
+
+// loggerLevels keeps the per-package levels hook of each logger
+// so level changes can tell the base level apart from the one
+// the logger runs at
+//
+//nolint:gochecknoglobals
+var (
+	loggerLevelsMu sync.RWMutex
+	loggerLevels   = map[*logrus.Logger]*levelsHook{}
+)
+
+// droppedLogger takes over the entries levelsHook drops so logrus
+// doesn't format them only to write them to io.Discard
+//
+//nolint:gochecknoglobals
+var droppedLogger = newDroppedLogger()
+
+func newDroppedLogger() *logrus.Logger {
+	logger := &logrus.Logger{
+		Out:       io.Discard,
+		Formatter: droppedFormatter{},
+		Hooks:     make(logrus.LevelHooks),
+		Level:     logrus.TraceLevel,
+		ExitFunc:  os.Exit,
+	}
+
+	logger.SetNoLock()
+
+	return logger
+}
+
+// droppedFormatter formats the dropped entries to nothing
+type droppedFormatter struct{}
+
+func (droppedFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
+
+// levelRule is a pattern=level pair from LOG_LEVELS. The pattern is
+// a component name, a package path or its last element, or a package
+// path prefix ending with /*.
+type levelRule struct {
+	pattern string
+	prefix  bool
+	level   logrus.Level
+}
+
+func (r levelRule) matchesPackage(pkg string) bool {
+	if r.prefix {
+		return pkg == r.pattern || strings.HasPrefix(pkg, r.pattern+"/")
+	}
+
+	return pkg == r.pattern || path.Base(pkg) == r.pattern
+}
+
+// getLevelRules parses the pattern=level pairs putting the
+// exact patterns first and the longest prefixes before the rest
+func getLevelRules(levels []string) ([]levelRule, error) {
+	rules := make([]levelRule, 0, len(levels))
+
+	for _, l := range levels {
+		pattern, lvl, ok := strings.Cut(strings.TrimSpace(l), "=")
+		pattern = strings.TrimSpace(pattern)
+
+		if !ok || pattern == "" || pattern == "*" {
+			return nil, errors.Wrap(errInvalidLogLevels, l)
+		}
+
+		logrusLevel, err := getLogrusLevel(level(strings.TrimSpace(lvl)))
+		if err != nil {
+			return nil, errors.Wrap(err, l)
+		}
+
+		rule := levelRule{pattern: pattern, level: logrusLevel}
+		if strings.HasSuffix(pattern, levelRuleWildcard) {
+			rule.pattern = strings.TrimSuffix(pattern, levelRuleWildcard)
+			rule.prefix = true
+		}
+
+		rules = append(rules, rule)
+	}
+
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].prefix != rules[j].prefix {
+			return !rules[i].prefix
+		}
+
+		return rules[i].prefix && len(rules[i].pattern) > len(rules[j].pattern)
+	})
+
+	return rules, nil
+}
+
+// levelsHook sits in front of the output hooks and drops the entries
+// below the level of the package that logged them. The logger runs
+// at the most verbose level of all so the entries get this far. The
+// callers get resolved once per call site and the dropped entries
+// are handed to droppedLogger, so what they cost on top of a disabled
+// level is a look at the stack. Logger.IsLevelEnabled can't tell them
+// apart, IsLevelEnabled can.
+type levelsHook struct {
+	rules []levelRule
+	// hooks get swapped whole as AddHook and SetHooks go behind
+	// this one too, so they only get the entries that aren't dropped
+	hooks atomic.Pointer[logrus.LevelHooks]
+	base  atomic.Uint32
+	// quietest is the least verbose rule level, everything at
+	// or above both it and the base level goes through as is
+	quietest logrus.Level
+	// loudest is the most verbose rule level
+	loudest logrus.Level
+	// packages caches the level of each caller function
+	packages sync.Map
+
+	hooksMu sync.Mutex
+}
+
+func newLevelsHook(rules []levelRule, base logrus.Level, hooks ...logrus.Hook) *levelsHook {
+	h := &levelsHook{
+		rules:    rules,
+		quietest: logrus.TraceLevel,
+		loudest:  logrus.PanicLevel,
+	}
+
+	h.setHooks(hooks...)
+
+	for _, rule := range rules {
+		h.quietest = min(h.quietest, rule.level)
+		h.loudest = max(h.loudest, rule.level)
+	}
+
+	h.base.Store(uint32(base))
+
+	return h
+}
+
+func (h *levelsHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *levelsHook) Fire(entry *logrus.Entry) error {
+	if !h.enabled(entry) {
+		entry.Logger = droppedLogger
+
+		return nil
+	}
+
+	return h.hooks.Load().Fire(entry.Level, entry)
+}
+
+func (h *levelsHook) setHooks(hooks ...logrus.Hook) {
+	levelHooks := make(logrus.LevelHooks)
+	for _, hook := range hooks {
+		levelHooks.Add(hook)
+	}
+
+	h.hooksMu.Lock()
+	h.hooks.Store(&levelHooks)
+	h.hooksMu.Unlock()
+}
+
+func (h *levelsHook) addHook(hook logrus.Hook) {
+	h.hooksMu.Lock()
+	defer h.hooksMu.Unlock()
+
+	levelHooks := make(logrus.LevelHooks)
+	for lvl, hooks := range *h.hooks.Load() {
+		levelHooks[lvl] = slices.Clone(hooks)
+	}
+
+	levelHooks.Add(hook)
+	h.hooks.Store(&levelHooks)
+}
+
+func (h *levelsHook) baseLevel() logrus.Level {
+	return logrus.Level(h.base.Load())
+}
+
+func (h *levelsHook) enabled(entry *logrus.Entry) bool {
+	base := h.baseLevel()
+	if entry.Level <= min(base, h.quietest) {
+		return true
+	}
+
+	lvl, ok := h.entryLevel(entry)
+	if !ok {
+		lvl = base
+	}
+
+	return entry.Level <= lvl
+}
+
+// entryLevel finds the rule for the component field of the
+// entry or else for the package of the function that logged it
+func (h *levelsHook) entryLevel(entry *logrus.Entry) (logrus.Level, bool) {
+	if component, ok := entry.Data[componentField].(string); ok {
+		for _, rule := range h.rules {
+			if !rule.prefix && rule.pattern == component {
+				return rule.level, true
+			}
+		}
+	}
+
+	var function string
+
+	if entry.Caller != nil {
+		function = entry.Caller.Function
+	} else {
+		function = getCallerFunctionAfter(logrusPackage)
+	}
+
+	if cached, ok := h.packages.Load(function); ok {
+		rule, _ := cached.(*levelRule)
+		if rule == nil {
+			return 0, false
+		}
+
+		return rule.level, true
+	}
+
+	pkg := getPackageName(function)
+
+	for i := range h.rules {
+		if h.rules[i].matchesPackage(pkg) {
+			h.packages.Store(function, &h.rules[i])
+
+			return h.rules[i].level, true
+		}
+	}
+
+	h.packages.Store(function, (*levelRule)(nil))
+
+	return 0, false
+}
+
+// packageLevel returns the level of the component or package
+func (h *levelsHook) packageLevel(pkg string) logrus.Level {
+	for i := range h.rules {
+		if h.rules[i].matchesPackage(pkg) {
+			return h.rules[i].level
+		}
+	}
+
+	return h.baseLevel()
+}
+
+// getPackageName trims the function, type and closure
+// names off a function name leaving the package path
+func getPackageName(function string) string {
+	for {
+		lastPeriod := strings.LastIndex(function, ".")
+		lastSlash := strings.LastIndex(function, "/")
+
+		if lastPeriod <= lastSlash {
+			return function
+		}
+
+		function = function[:lastPeriod]
+	}
+}
+
+// IsLevelEnabled tells whether the standard logger logs the level for the
+// component or package, taking LOG_LEVELS into account unlike
+// logrus.IsLevelEnabled. Guard the expensive log calls with it.
+func IsLevelEnabled(pkg string, lvl logrus.Level) bool {
+	return IsLoggerLevelEnabled(logrus.StandardLogger(), pkg, lvl)
+}
+
+// IsLoggerLevelEnabled is IsLevelEnabled for any logger
+func IsLoggerLevelEnabled(logger *logrus.Logger, pkg string, lvl logrus.Level) bool {
+	hook := getLoggerLevels(logger)
+	if hook == nil {
+		return logger.IsLevelEnabled(lvl)
+	}
+
+	return lvl <= hook.packageLevel(pkg)
+}
+
+func setLoggerLevels(logger *logrus.Logger, hook *levelsHook) {
+	loggerLevelsMu.Lock()
+	defer loggerLevelsMu.Unlock()
+
+	if hook == nil {
+		delete(loggerLevels, logger)
+
+		return
+	}
+
+	loggerLevels[logger] = hook
+}
+
+func getLoggerLevels(logger *logrus.Logger) *levelsHook {
+	loggerLevelsMu.RLock()
+	defer loggerLevelsMu.RUnlock()
+
+	return loggerLevels[logger]
+}
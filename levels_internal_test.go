@@ -0,0 +1,319 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLevelRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		levels        []string
+		expected      []levelRule
+		expectedError string
+	}{
+		{
+			name:     "None",
+			expected: []levelRule{},
+		},
+		{
+			name:   "Exact before longest prefix",
+			levels: []string{"github.com/foo/*=error", " db = debug ", "github.com/foo/bar/*=info", "http=warn"},
+			expected: []levelRule{
+				{pattern: "db", level: logrus.DebugLevel},
+				{pattern: "http", level: logrus.WarnLevel},
+				{pattern: "github.com/foo/bar", prefix: true, level: logrus.InfoLevel},
+				{pattern: "github.com/foo", prefix: true, level: logrus.ErrorLevel},
+			},
+		},
+		{
+			name:          "Missing level",
+			levels:        []string{"db"},
+			expectedError: "db: invalid per-package log level",
+		},
+		{
+			name:          "Catch-all pattern",
+			levels:        []string{"*=debug"},
+			expectedError: "*=debug: invalid per-package log level",
+		},
+		{
+			name:          "Invalid level",
+			levels:        []string{"db=loud"},
+			expectedError: "db=loud: loud: invalid log level",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := getLevelRules(tc.levels)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rules)
+		})
+	}
+}
+
+func TestGetPackageName(t *testing.T) {
+	testCases := map[string]string{
+		"main.main":                         "main",
+		"github.com/foo/bar.Func":           "github.com/foo/bar",
+		"github.com/foo/bar.(*Type).Method": "github.com/foo/bar",
+		"github.com/foo/bar.Func.func1":     "github.com/foo/bar",
+	}
+
+	for function, expected := range testCases {
+		assert.Equal(t, expected, getPackageName(function), function)
+	}
+}
+
+func TestLevelsHook(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithLevel("info"),
+		WithFormat("json"),
+		WithPackageLevels("db=debug", "http=error", "github.com/psyb0t/*=trace"),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+
+	// the logger runs at the most verbose level while
+	// the base level stays what was configured
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, getLoggerBaseLevel(logger))
+
+	testCases := []struct {
+		name     string
+		log      func()
+		expected bool
+	}{
+		{
+			name:     "Component more verbose",
+			log:      func() { logger.WithField(componentField, "db").Debug("query") },
+			expected: true,
+		},
+		{
+			name:     "Component less verbose",
+			log:      func() { logger.WithField(componentField, "http").Warn("slow") },
+			expected: false,
+		},
+		{
+			name:     "Unknown component falls back to the package",
+			log:      func() { logger.WithField(componentField, "cache").Trace("miss") },
+			expected: true,
+		},
+		{
+			name:     "Enabled everywhere",
+			log:      func() { logger.WithField(componentField, "http").Error("down") },
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.log()
+			assert.Equal(t, tc.expected, buf.Len() > 0, buf.String())
+		})
+	}
+}
+
+func TestLevelsHookBaseLevel(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithLevel("info"),
+		WithPackageLevels("db=warn", "github.com/foo/*=debug"),
+		WithReportCaller(true),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+
+	// this package has no rule so the base level applies
+	logger.Debug("hidden")
+	assert.Empty(t, buf.String())
+
+	logger.Info("shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	setLoggerBaseLevel(logger, logrus.ErrorLevel)
+	assert.Equal(t, logrus.DebugLevel, logger.GetLevel())
+	assert.Equal(t, logrus.ErrorLevel, getLoggerBaseLevel(logger))
+
+	buf.Reset()
+	logger.Warn("hidden")
+	assert.Empty(t, buf.String())
+
+	setLoggerBaseLevel(logger, logrus.TraceLevel)
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+
+	logger.Trace("shown")
+	assert.Contains(t, buf.String(), "shown")
+
+	// without rules the hook goes away
+	require.NoError(t, ConfigureLogger(logger, WithLevel("warn"), WithHooks()))
+	assert.Nil(t, getLoggerLevels(logger))
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel())
+}
+
+type funcHook struct {
+	fire func(entry *logrus.Entry)
+}
+
+func (h *funcHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *funcHook) Fire(entry *logrus.Entry) error {
+	h.fire(entry)
+
+	return nil
+}
+
+func TestLevelsHookGatesAddedHooks(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithLevel("info"),
+		WithFormat("json"),
+		WithPackageLevels("db=debug"),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+
+	added := newGatedHook()
+	close(added.gate)
+	addLoggerHook(logger, added)
+
+	// added to logrus directly, so it gets the dropped entries too
+	var raw []*logrus.Entry
+
+	logger.AddHook(&funcHook{fire: func(entry *logrus.Entry) { raw = append(raw, entry) }})
+
+	logger.Debug("dropped")
+	logger.WithField(componentField, "db").Debug("kept")
+
+	assert.Equal(t, []string{"kept"}, added.getMessages())
+	assert.NotContains(t, buf.String(), "dropped")
+	assert.Contains(t, buf.String(), "kept")
+
+	require.Len(t, raw, 2)
+	assert.Same(t, droppedLogger, raw[0].Logger, "Expected the dropped entry to skip the formatting")
+
+	formatted, err := raw[0].Bytes()
+	require.NoError(t, err)
+	assert.Empty(t, formatted)
+
+	assert.Same(t, logger, raw[1].Logger)
+}
+
+func TestSetHooksBehindPackageLevels(t *testing.T) {
+	unsetEnvs(t)
+	require.NoError(t, Configure(WithPackageLevels("db=debug")))
+
+	t.Cleanup(func() { require.NoError(t, Configure()) })
+
+	added := newGatedHook()
+	close(added.gate)
+
+	SetHooks(added)
+	logrus.Debug("dropped")
+	logrus.WithField(componentField, "db").Debug("kept")
+
+	assert.Equal(t, []string{"kept"}, added.getMessages())
+}
+
+func TestIsLoggerLevelEnabled(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(
+		WithLevel("info"),
+		WithPackageLevels("db=debug", "http=error", "github.com/foo/*=trace"),
+		WithHooks(),
+	)
+	require.NoError(t, err)
+
+	// logrus can't tell as the logger runs at trace
+	assert.True(t, logger.IsLevelEnabled(logrus.DebugLevel))
+
+	assert.True(t, IsLoggerLevelEnabled(logger, "db", logrus.DebugLevel))
+	assert.True(t, IsLoggerLevelEnabled(logger, "myapp/internal/db", logrus.DebugLevel))
+	assert.False(t, IsLoggerLevelEnabled(logger, "db", logrus.TraceLevel))
+	assert.False(t, IsLoggerLevelEnabled(logger, "http", logrus.WarnLevel))
+	assert.True(t, IsLoggerLevelEnabled(logger, "github.com/foo/bar", logrus.TraceLevel))
+	assert.False(t, IsLoggerLevelEnabled(logger, "cache", logrus.DebugLevel))
+	assert.True(t, IsLoggerLevelEnabled(logger, "cache", logrus.InfoLevel))
+
+	setLoggerBaseLevel(logger, logrus.WarnLevel)
+	assert.False(t, IsLoggerLevelEnabled(logger, "cache", logrus.InfoLevel))
+
+	require.NoError(t, ConfigureLogger(logger, WithLevel("warn"), WithHooks()))
+	assert.False(t, IsLoggerLevelEnabled(logger, "db", logrus.DebugLevel))
+	assert.True(t, IsLoggerLevelEnabled(logger, "db", logrus.WarnLevel))
+}
+
+// BenchmarkLevelsHook shows what a debug entry nobody wants costs
+// once another package turns debug on and what the guard saves
+func BenchmarkLevelsHook(b *testing.B) {
+	unsetEnvs(b)
+
+	testCases := []struct {
+		name   string
+		levels []string
+		log    func(logger *logrus.Logger)
+	}{
+		{
+			name: "Without package levels",
+			log:  func(logger *logrus.Logger) { logger.WithField("n", 1).Debug("dropped") },
+		},
+		{
+			name:   "With package levels",
+			levels: []string{"db=debug"},
+			log:    func(logger *logrus.Logger) { logger.WithField("n", 1).Debug("dropped") },
+		},
+		{
+			name:   "With package levels guarded",
+			levels: []string{"db=debug"},
+			log: func(logger *logrus.Logger) {
+				if IsLoggerLevelEnabled(logger, "cache", logrus.DebugLevel) {
+					logger.WithField("n", 1).Debug("dropped")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		b.Run(tc.name, func(b *testing.B) {
+			logger, err := NewLogger(
+				WithLevel("info"),
+				WithFormat("json"),
+				WithPackageLevels(tc.levels...),
+				WithHooks(getAllLevelsHook(io.Discard)),
+			)
+			require.NoError(b, err)
+
+			b.ReportAllocs()
+
+			for b.Loop() {
+				tc.log(logger)
+			}
+		})
+	}
+}
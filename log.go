@@ -2,6 +2,11 @@ package logrusconfigurator
 
 import (
 	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/psyb0t/gonfiguration"
@@ -10,24 +15,126 @@ import (
 
 const (
 	configKeyLogLevel  = "LOG_LEVEL"
+	configKeyLogLevels = "LOG_LEVELS"
 	configKeyLogFormat = "LOG_FORMAT"
 	configKeyLogCaller = "LOG_CALLER"
+	configKeyLogColor  = "LOG_COLOR"
+	configKeyLogOutput = "LOG_OUTPUT"
+	configKeyLogSlog   = "LOG_SLOG"
+
+	configKeyLogSplit        = "LOG_SPLIT"
+	configKeyLogStderrLevels = "LOG_STDERR_LEVELS"
+
+	configKeyLogFile           = "LOG_FILE"
+	configKeyLogFileMaxSize    = "LOG_FILE_MAX_SIZE"
+	configKeyLogFileMaxAge     = "LOG_FILE_MAX_AGE"
+	configKeyLogFileMaxBackups = "LOG_FILE_MAX_BACKUPS"
+	configKeyLogFileCompress   = "LOG_FILE_COMPRESS"
+
+	configKeyLogConfigFile = "LOG_CONFIG_FILE"
+
+	configKeyLogCaptureStdlib      = "LOG_CAPTURE_STDLIB"
+	configKeyLogCaptureStdlibLevel = "LOG_CAPTURE_STDLIB_LEVEL"
+
+	configKeyLogAsync          = "LOG_ASYNC"
+	configKeyLogAsyncQueueSize = "LOG_ASYNC_QUEUE_SIZE"
+	configKeyLogAsyncOverflow  = "LOG_ASYNC_OVERFLOW"
+	configKeyLogAsyncDropLevel = "LOG_ASYNC_DROP_LEVEL"
+
+	configKeyLogSampling = "LOG_SAMPLING"
+
+	configKeyLogDedupeWindow = "LOG_DEDUPE_WINDOW"
+	configKeyLogDedupeFields = "LOG_DEDUPE_FIELDS"
+	configKeyLogRateLimit    = "LOG_RATE_LIMIT"
+
+	configKeyLogRedact         = "LOG_REDACT"
+	configKeyLogRedactFields   = "LOG_REDACT_FIELDS"
+	configKeyLogRedactMessages = "LOG_REDACT_MESSAGES"
+	configKeyLogRedactMode     = "LOG_REDACT_MODE"
+	configKeyLogRedactHashKey  = "LOG_REDACT_HASH_KEY"
 )
 
 const (
 	defaultReportCaller = false
 	defaultLevel        = levelInfo
 	defaultFormat       = formatText
+	defaultSplit        = splitDefault
+	defaultColor        = colorAuto
+	defaultSlog         = false
+
+	defaultFileMaxSize    = 100
+	defaultFileMaxAge     = time.Duration(0)
+	defaultFileMaxBackups = 0
+	defaultFileCompress   = false
+
+	defaultCaptureStdlib      = false
+	defaultCaptureStdlibLevel = levelInfo
+
+	defaultAsync          = false
+	defaultAsyncQueueSize = 1024
+	defaultAsyncOverflow  = overflowBlock
+	defaultAsyncDropLevel = levelWarn
+
+	defaultDedupeWindow = time.Duration(0)
+
+	defaultRedact     = false
+	defaultRedactMode = redactModeMask
 )
 
+// loggerConfigs keeps the config last applied to each logger
+//
+//nolint:gochecknoglobals
+var (
+	loggerConfigsMu sync.RWMutex
+	loggerConfigs   = map[*logrus.Logger]Config{}
+)
+
+// reconfigureMu serializes the changes made to the loggers so
+// configuring, signals and the admin handler don't interleave
+//
+//nolint:gochecknoglobals
+var reconfigureMu sync.Mutex
+
 type config struct {
-	Level        level  `env:"LOG_LEVEL"`
-	Format       format `env:"LOG_FORMAT"`
-	ReportCaller bool   `env:"LOG_CALLER"`
+	Level          level         `env:"LOG_LEVEL"`
+	Format         format        `env:"LOG_FORMAT"`
+	ReportCaller   bool          `env:"LOG_CALLER"`
+	Color          color         `env:"LOG_COLOR"`
+	Outputs        []string      `env:"LOG_OUTPUT"`
+	Split          split         `env:"LOG_SPLIT"`
+	StderrLevels   []string      `env:"LOG_STDERR_LEVELS"`
+	PackageLevels  []string      `env:"LOG_LEVELS"`
+	File           string        `env:"LOG_FILE"`
+	FileMaxSize    int           `env:"LOG_FILE_MAX_SIZE"`
+	FileMaxAge     time.Duration `env:"LOG_FILE_MAX_AGE"`
+	FileMaxBackups int           `env:"LOG_FILE_MAX_BACKUPS"`
+	FileCompress   bool          `env:"LOG_FILE_COMPRESS"`
+	ConfigFile     string        `env:"LOG_CONFIG_FILE"`
+	Slog           bool          `env:"LOG_SLOG"`
+
+	CaptureStdlib      bool  `env:"LOG_CAPTURE_STDLIB"`
+	CaptureStdlibLevel level `env:"LOG_CAPTURE_STDLIB_LEVEL"`
+
+	Async          bool     `env:"LOG_ASYNC"`
+	AsyncQueueSize int      `env:"LOG_ASYNC_QUEUE_SIZE"`
+	AsyncOverflow  overflow `env:"LOG_ASYNC_OVERFLOW"`
+	AsyncDropLevel level    `env:"LOG_ASYNC_DROP_LEVEL"`
+
+	Sampling []string `env:"LOG_SAMPLING"`
+
+	DedupeWindow time.Duration `env:"LOG_DEDUPE_WINDOW"`
+	DedupeFields []string      `env:"LOG_DEDUPE_FIELDS"`
+	RateLimit    []string      `env:"LOG_RATE_LIMIT"`
+
+	Redact         bool       `env:"LOG_REDACT"`
+	RedactFields   []string   `env:"LOG_REDACT_FIELDS"`
+	RedactMessages []string   `env:"LOG_REDACT_MESSAGES"`
+	RedactMode     redactMode `env:"LOG_REDACT_MODE"`
+	RedactHashKey  string     `env:"LOG_REDACT_HASH_KEY" secret:"true"`
 }
 
-func (c config) log() {
-	logrus.Debugf(
+func (c config) log(logger *logrus.Logger) {
+	logger.Debugf(
 		"logrus-configurator: level: %s, format: %s, reportCaller: %t",
 		c.Level,
 		c.Format,
@@ -35,44 +142,469 @@ func (c config) log() {
 	)
 }
 
+func (c config) export() Config {
+	return Config{
+		Level:        string(c.Level),
+		Format:       string(c.Format),
+		ReportCaller: c.ReportCaller,
+		Color:        string(c.Color),
+		Outputs:      c.Outputs,
+		Split:        string(c.Split),
+		StderrLevels: c.StderrLevels,
+		File: FileConfig{
+			Path:       c.File,
+			MaxSize:    c.FileMaxSize,
+			MaxAge:     c.FileMaxAge,
+			MaxBackups: c.FileMaxBackups,
+			Compress:   c.FileCompress,
+		},
+		PackageLevels: c.PackageLevels,
+		ConfigFile:    c.ConfigFile,
+		Slog:          c.Slog,
+
+		CaptureStdlib:      c.CaptureStdlib,
+		CaptureStdlibLevel: string(c.CaptureStdlibLevel),
+
+		Async: AsyncConfig{
+			Enabled:   c.Async,
+			QueueSize: c.AsyncQueueSize,
+			Overflow:  string(c.AsyncOverflow),
+			DropLevel: string(c.AsyncDropLevel),
+		},
+		Sampling: c.Sampling,
+		Dedupe: DedupeConfig{
+			Window: c.DedupeWindow,
+			Fields: c.DedupeFields,
+		},
+		RateLimit: c.RateLimit,
+		Redact: RedactConfig{
+			Enabled:  c.Redact,
+			Fields:   c.RedactFields,
+			Messages: c.RedactMessages,
+			Mode:     string(c.RedactMode),
+			HashKey:  c.RedactHashKey,
+		},
+	}
+}
+
+// Config is the exported counterpart of the env-driven config. Configure
+// fills it from the environment first and then applies the given options
+// on top of it.
+type Config struct {
+	// EnvPrefix namespaces the env vars, e.g. AUDIT reads AUDIT_LOG_LEVEL
+	EnvPrefix    string
+	Level        string
+	Format       string
+	ReportCaller bool
+	// Color colors the pretty format: auto (when stdout is
+	// a terminal and NO_COLOR isn't set), always or never
+	Color string
+	// Outputs lists the log destinations: stdout, stderr, file:///path,
+	// tcp://host:port, unix:///path, gelf+udp://host:port or
+	// gelf+tcp://host:port. Empty means stdout and stderr.
+	Outputs []string
+	// Split decides which levels go to stdout and which to stderr:
+	// default (warn and up to stderr), off (all to stdout) or custom
+	Split string
+	// StderrLevels are the levels sent to stderr with the custom split,
+	// setting them with any other split is an error
+	StderrLevels []string
+	// PackageLevels override the level per component field or caller
+	// package as pattern=level, e.g. db=debug or github.com/foo/*=error
+	PackageLevels []string
+	// File adds a rotating log file output when its Path is set
+	File FileConfig
+	// ConfigFile is a YAML, JSON or TOML file with more settings. It's
+	// read on top of the defaults and the env vars win over it.
+	ConfigFile string
+	// Slog installs a SlogHandler for the logger as the slog default
+	Slog bool
+	// CaptureStdlib sends the log package output to the logger
+	// at CaptureStdlibLevel, info when it's empty
+	CaptureStdlib      bool
+	CaptureStdlibLevel string
+	// Async writes to the outputs on a goroutine of their own
+	Async AsyncConfig
+	// Sampling caps the entries logged with the same message per level
+	// as level=first/thereafter/interval, e.g. info=100/10/1s
+	Sampling []string
+	// Dedupe collapses the entries repeated within its window
+	Dedupe DedupeConfig
+	// RateLimit caps the entries logged per level as
+	// level=limit/interval, e.g. error=100/1s
+	RateLimit []string
+	// Redact hides the sensitive fields and message parts from every output
+	Redact RedactConfig
+	// Hooks replaces the output hooks when not nil
+	Hooks []logrus.Hook
+	// FieldHooks fire before the output hooks so
+	// the fields they add make it to the outputs
+	FieldHooks []logrus.Hook
+}
+
+func (c Config) internal() config {
+	return config{
+		Level:        level(c.Level),
+		Format:       format(c.Format),
+		ReportCaller: c.ReportCaller,
+		Color:        color(c.Color),
+		Outputs:      c.Outputs,
+		Split:        split(c.Split),
+		StderrLevels: c.StderrLevels,
+
+		File:           c.File.Path,
+		FileMaxSize:    c.File.MaxSize,
+		FileMaxAge:     c.File.MaxAge,
+		FileMaxBackups: c.File.MaxBackups,
+		FileCompress:   c.File.Compress,
+
+		PackageLevels: c.PackageLevels,
+		ConfigFile:    c.ConfigFile,
+		Slog:          c.Slog,
+
+		CaptureStdlib:      c.CaptureStdlib,
+		CaptureStdlibLevel: level(c.CaptureStdlibLevel),
+
+		Async:          c.Async.Enabled,
+		AsyncQueueSize: c.Async.QueueSize,
+		AsyncOverflow:  overflow(c.Async.Overflow),
+		AsyncDropLevel: level(c.Async.DropLevel),
+
+		Sampling: c.Sampling,
+
+		DedupeWindow: c.Dedupe.Window,
+		DedupeFields: c.Dedupe.Fields,
+		RateLimit:    c.RateLimit,
+
+		Redact:         c.Redact.Enabled,
+		RedactFields:   c.Redact.Fields,
+		RedactMessages: c.Redact.Messages,
+		RedactMode:     redactMode(c.Redact.Mode),
+		RedactHashKey:  c.Redact.HashKey,
+	}
+}
+
 //nolint:gochecknoinits
 func init() {
-	if err := configure(); err != nil {
-		logrus.Panic(err)
+	logger := logrus.StandardLogger()
+
+	logrus.RegisterExitHandler(shutdownOnExit)
+
+	if err := configure(logger); err != nil {
+		// don't blow up at import time, fall back to the defaults and
+		// let the app call Configure to get the error back
+		if err := apply(logger, defaultConfig().export()); err != nil {
+			logrus.Panic(err)
+		}
+
+		logger.WithError(err).Error("logrus-configurator: invalid log config, using defaults")
 	}
 }
 
-func configure() error {
-	setDefaults()
+// Configure (re)configures the standard logger from the environment
+// with the given options applied on top. It can be called any number of
+// times, e.g. once more after the app's flags are parsed.
+func Configure(opts ...Option) error {
+	return configure(logrus.StandardLogger(), opts...)
+}
+
+// ConfigureLogger does what Configure does but for the given logger
+func ConfigureLogger(logger *logrus.Logger, opts ...Option) error {
+	return configure(logger, opts...)
+}
+
+// NewLogger creates a new logger configured the same way Configure
+// configures the standard one
+func NewLogger(opts ...Option) (*logrus.Logger, error) {
+	logger := logrus.New()
+
+	if err := configure(logger, opts...); err != nil {
+		return nil, err
+	}
+
+	return logger, nil
+}
+
+func configure(logger *logrus.Logger, opts ...Option) error {
+	// the options have to be applied once upfront because the env
+	// prefix decides which env vars get parsed and the config file
+	// has to be read before the options override what's in it
+	cfg := Config{}
+	applyOptions(&cfg, opts...)
 
+	prefix := cfg.EnvPrefix
+	setDefaults(prefix)
+
+	c, err := parseConfig(prefix)
+	if err != nil {
+		return err
+	}
+
+	if cfg.ConfigFile != "" {
+		c.ConfigFile = cfg.ConfigFile
+	}
+
+	if c.ConfigFile != "" {
+		f, err := readConfigFile(c.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		if err := f.merge(&c, prefix); err != nil {
+			return err
+		}
+	}
+
+	cfg = c.export()
+	cfg.EnvPrefix = prefix
+	applyOptions(&cfg, opts...)
+
+	return apply(logger, cfg)
+}
+
+// parseConfig parses the config from the env vars named by
+// the config struct tags with the given prefix prepended
+func parseConfig(prefix string) (config, error) {
 	c := config{}
-	if err := gonfiguration.Parse(&c); err != nil {
-		return errors.Wrap(err, "failed to parse log config")
+	configType := reflect.TypeFor[config]()
+
+	fields := make([]reflect.StructField, 0, configType.NumField())
+	for i := range configType.NumField() {
+		field := configType.Field(i)
+		if tag, ok := field.Tag.Lookup("env"); ok {
+			field.Tag = reflect.StructTag(`env:"` + envKey(prefix, tag) + `"`)
+		}
+
+		fields = append(fields, field)
+	}
+
+	prefixed := reflect.New(reflect.StructOf(fields))
+	if err := gonfiguration.Parse(prefixed.Interface()); err != nil {
+		return c, errors.Wrap(err, "failed to parse log config")
 	}
 
-	if err := setLevel(c.Level); err != nil {
+	reflect.ValueOf(&c).Elem().Set(prefixed.Elem().Convert(configType))
+
+	return c, nil
+}
+
+func envKey(prefix string, key string) string {
+	if prefix == "" {
+		return key
+	}
+
+	return strings.TrimSuffix(prefix, "_") + "_" + key
+}
+
+// apply validates the whole config and builds the formatter and the
+// hooks before touching the logger so a bad config leaves it as it was
+func apply(logger *logrus.Logger, cfg Config) error {
+	c := cfg.internal()
+
+	logrusLevel, err := getLogrusLevel(c.Level)
+	if err != nil {
 		return errors.Wrap(err, "failed to set log level")
 	}
 
-	logrus.SetOutput(io.Discard)
-	logrus.SetReportCaller(c.ReportCaller)
+	rules, err := getLevelRules(c.PackageLevels)
+	if err != nil {
+		return errors.Wrap(err, "failed to set log level")
+	}
+
+	samplingRules, err := getSamplingRules(c.Sampling)
+	if err != nil {
+		return errors.Wrap(err, "failed to set log sampling")
+	}
+
+	if c.DedupeWindow < 0 {
+		return errors.Wrapf(errInvalidLogDedupe, "failed to set log dedupe: %s", c.DedupeWindow)
+	}
+
+	rateLimits, err := getRateLimits(c.RateLimit)
+	if err != nil {
+		return errors.Wrap(err, "failed to set log rate limit")
+	}
+
+	var redact *redactHook
+	if cfg.Redact.Enabled {
+		if redact, err = newRedactHook(cfg.Redact); err != nil {
+			return errors.Wrap(err, "failed to set log redaction")
+		}
+	}
 
-	if err := setFormat(c.Format); err != nil {
+	stdlibLevel := logrus.InfoLevel
+	if c.CaptureStdlib && c.CaptureStdlibLevel != "" {
+		if stdlibLevel, err = getStdlibLevel(c.CaptureStdlibLevel); err != nil {
+			return errors.Wrap(err, "failed to set stdlib log capture level")
+		}
+	}
+
+	formatter, err := getLogrusFormat(c.Format)
+	if err != nil {
 		return errors.Wrap(err, "failed to set log format")
 	}
 
-	clearLoggerHooks(logrus.StandardLogger())
-	addLoggerDefaultHooks(logrus.StandardLogger())
+	colorEnabled, err := isColorEnabled(c.Color)
+	if err != nil {
+		return errors.Wrap(err, "failed to set log color")
+	}
+
+	hooks, closers, err := getConfigHooks(cfg)
+	if err != nil {
+		return errors.Wrap(err, "failed to set log outputs")
+	}
+
+	setFormatterColor(formatter, colorEnabled)
+
+	outputFormat := newOutputFormat(formatter, c.ReportCaller)
+	hooks = bindOutputFormat(hooks, outputFormat)
+
+	if len(hooks) > 1 {
+		hooks = []logrus.Hook{getOutputsHook(hooks)}
+	}
+
+	var async *AsyncHook
+	if cfg.Async.Enabled {
+		if async, err = NewAsyncHook(getOutputsHook(hooks), cfg.Async); err != nil {
+			_ = closeOutputs(closers)
+
+			return errors.Wrap(err, "failed to set log outputs")
+		}
+
+		// the queue has to be drained before the outputs get closed
+		hooks = []logrus.Hook{async}
+		closers = append([]io.Closer{async}, closers...)
+	}
+
+	if redact != nil {
+		hooks = append([]logrus.Hook{redact}, hooks...)
+	}
 
-	c.log()
+	hooks = append(slices.Clone(cfg.FieldHooks), hooks...)
+
+	if len(samplingRules) > 0 {
+		// the summary has to make it out before the outputs get closed
+		sampling := newSamplingHook(logger, samplingRules, hooks...)
+		hooks = []logrus.Hook{sampling}
+		closers = append([]io.Closer{sampling}, closers...)
+	}
+
+	if len(rateLimits) > 0 {
+		hooks = []logrus.Hook{newRateLimitHook(rateLimits, hooks...)}
+	}
+
+	if c.DedupeWindow > 0 {
+		// the held back repeats have to make it out before the outputs get closed
+		dedupe := newDedupeHook(cfg.Dedupe, hooks...)
+		hooks = []logrus.Hook{dedupe}
+		closers = append([]io.Closer{dedupe}, closers...)
+	}
+
+	var levels *levelsHook
+	if len(rules) > 0 {
+		levels = newLevelsHook(rules, logrusLevel, hooks...)
+		hooks = []logrus.Hook{levels}
+	}
+
+	// the context fields and the callers the bridges stored in the
+	// context have to be on the entry before any other hook sees it
+	hooks = append([]logrus.Hook{contextHook{}}, hooks...)
+
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+
+	setLoggerLevels(logger, levels)
+	setLoggerAsync(logger, async)
+	setLoggerBaseLevel(logger, logrusLevel)
+	logger.SetOutput(io.Discard)
+	logger.SetReportCaller(c.ReportCaller)
+	// the output hooks don't read the logger's formatter, it's only
+	// kept in sync for logrus itself and the hooks added elsewhere
+	logger.SetFormatter(formatter)
+	setLoggerHooks(logger, hooks...)
+	setLoggerOutputFormat(logger, outputFormat)
+
+	if err := setLoggerOutputs(logger, closers); err != nil {
+		logger.WithError(err).Warn("logrus-configurator: failed to close previous log outputs")
+	}
+
+	setLoggerConfig(logger, cfg)
+	setSlogDefault(logger, c.Slog)
+	setStdlibCapture(logger, c.CaptureStdlib, stdlibLevel)
+	c.log(logger)
 
 	return nil
 }
 
-func setDefaults() {
+func setLoggerConfig(logger *logrus.Logger, cfg Config) {
+	loggerConfigsMu.Lock()
+	defer loggerConfigsMu.Unlock()
+
+	loggerConfigs[logger] = cfg
+}
+
+func getLoggerConfig(logger *logrus.Logger) (Config, bool) {
+	loggerConfigsMu.RLock()
+	defer loggerConfigsMu.RUnlock()
+
+	cfg, ok := loggerConfigs[logger]
+
+	return cfg, ok
+}
+
+func defaultConfig() config {
+	return config{
+		Level:          defaultLevel,
+		Format:         defaultFormat,
+		ReportCaller:   defaultReportCaller,
+		Color:          defaultColor,
+		Split:          defaultSplit,
+		Slog:           defaultSlog,
+		FileMaxSize:    defaultFileMaxSize,
+		FileMaxAge:     defaultFileMaxAge,
+		FileMaxBackups: defaultFileMaxBackups,
+		FileCompress:   defaultFileCompress,
+
+		CaptureStdlib:      defaultCaptureStdlib,
+		CaptureStdlibLevel: defaultCaptureStdlibLevel,
+
+		Async:          defaultAsync,
+		AsyncQueueSize: defaultAsyncQueueSize,
+		AsyncOverflow:  defaultAsyncOverflow,
+		AsyncDropLevel: defaultAsyncDropLevel,
+
+		DedupeWindow: defaultDedupeWindow,
+
+		Redact:     defaultRedact,
+		RedactMode: defaultRedactMode,
+	}
+}
+
+func setDefaults(prefix string) {
 	gonfiguration.SetDefaults(map[string]any{
-		configKeyLogLevel:  defaultLevel,
-		configKeyLogFormat: defaultFormat,
-		configKeyLogCaller: defaultReportCaller,
+		envKey(prefix, configKeyLogLevel):  defaultLevel,
+		envKey(prefix, configKeyLogFormat): defaultFormat,
+		envKey(prefix, configKeyLogCaller): defaultReportCaller,
+		envKey(prefix, configKeyLogColor):  defaultColor,
+		envKey(prefix, configKeyLogSplit):  defaultSplit,
+		envKey(prefix, configKeyLogSlog):   defaultSlog,
+
+		envKey(prefix, configKeyLogFileMaxSize):    defaultFileMaxSize,
+		envKey(prefix, configKeyLogFileMaxAge):     defaultFileMaxAge,
+		envKey(prefix, configKeyLogFileMaxBackups): defaultFileMaxBackups,
+		envKey(prefix, configKeyLogFileCompress):   defaultFileCompress,
+
+		envKey(prefix, configKeyLogCaptureStdlib):      defaultCaptureStdlib,
+		envKey(prefix, configKeyLogCaptureStdlibLevel): defaultCaptureStdlibLevel,
+
+		envKey(prefix, configKeyLogAsync):          defaultAsync,
+		envKey(prefix, configKeyLogAsyncQueueSize): defaultAsyncQueueSize,
+		envKey(prefix, configKeyLogAsyncOverflow):  defaultAsyncOverflow,
+		envKey(prefix, configKeyLogAsyncDropLevel): defaultAsyncDropLevel,
+
+		envKey(prefix, configKeyLogDedupeWindow): defaultDedupeWindow,
+
+		envKey(prefix, configKeyLogRedact):     defaultRedact,
+		envKey(prefix, configKeyLogRedactMode): defaultRedactMode,
 	})
 }
@@ -1,6 +1,7 @@
 package logrusconfigurator
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/sirupsen/logrus"
@@ -10,8 +11,8 @@ import (
 
 func TestSetDefaults(t *testing.T) {
 	unsetEnvs(t)
-	setDefaults()
-	require.NoError(t, configure(), "Unexpected error")
+	setDefaults("")
+	require.NoError(t, Configure(), "Unexpected error")
 
 	actualFormatter := logrus.StandardLogger().Formatter
 	defaultFormatter, err := getLogrusFormat(defaultFormat)
@@ -63,7 +64,7 @@ func TestConfigure(t *testing.T) {
 			t.Setenv(configKeyLogLevel, tc.logLevel)
 			t.Setenv(configKeyLogFormat, tc.logFormat)
 
-			err := configure()
+			err := Configure()
 
 			if tc.expectError {
 				require.Error(t, err, "Expected error")
@@ -120,7 +121,7 @@ func TestConfigLog(t *testing.T) {
 
 			// This should not panic or error - just exercise the log method
 			assert.NotPanics(t, func() {
-				c.log()
+				c.log(logrus.StandardLogger())
 			}, "config.log() should not panic")
 		})
 	}
@@ -168,7 +169,7 @@ func TestConfigureErrorHandling(t *testing.T) {
 			t.Setenv(configKeyLogFormat, tc.logFormat)
 			t.Setenv(configKeyLogCaller, tc.logCaller)
 
-			err := configure()
+			err := Configure()
 
 			if tc.expectError {
 				require.Error(t, err, "Expected error for test case: %s", tc.name)
@@ -179,3 +180,145 @@ func TestConfigureErrorHandling(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigureWithOptions(t *testing.T) {
+	originalHooks := logrus.StandardLogger().Hooks
+	defer func() {
+		logrus.StandardLogger().Hooks = originalHooks
+	}()
+
+	t.Setenv(configKeyLogLevel, "warn")
+	t.Setenv(configKeyLogFormat, "text")
+
+	buffer := &bytes.Buffer{}
+
+	err := Configure(
+		WithLevel("debug"),
+		WithFormat("json"),
+		WithReportCaller(true),
+		WithHooks(getStdoutHook(buffer)),
+	)
+	require.NoError(t, err, "Unexpected error")
+
+	assert.Equal(t, logrus.DebugLevel, logrus.GetLevel(), "Log level mismatch")
+	assert.IsType(t, &logrus.JSONFormatter{}, logrus.StandardLogger().Formatter, "Formatter type mismatch")
+	assert.True(t, logrus.StandardLogger().ReportCaller, "ReportCaller mismatch")
+	assert.Len(t, getConfiguredHooks(logrus.StandardLogger()), 3, "Expected only the custom hook levels")
+
+	logrus.Info("hello")
+	assert.Contains(t, buffer.String(), `"msg":"hello"`, "Custom hook should receive the entry")
+
+	require.NoError(t, Configure(), "Unexpected error")
+	assert.Equal(t, logrus.WarnLevel, logrus.GetLevel(), "Env level should apply without options")
+	assert.Len(t, logrus.StandardLogger().Hooks, 7, "Expected the default hook levels")
+}
+
+func TestConfigureWithInvalidOptions(t *testing.T) {
+	unsetEnvs(t)
+
+	err := Configure(WithLevel("nope"))
+	require.Error(t, err, "Expected error")
+	assert.Contains(t, err.Error(), "failed to set log level")
+
+	err = Configure(WithFormat("nope"))
+	require.Error(t, err, "Expected error")
+	assert.Contains(t, err.Error(), "failed to set log format")
+}
+
+func TestNewLogger(t *testing.T) {
+	unsetEnvs(t)
+
+	originalLevel := logrus.GetLevel()
+
+	logger, err := NewLogger(WithLevel("trace"), WithFormat("json"))
+	require.NoError(t, err, "Unexpected error")
+	require.NotNil(t, logger, "Logger should not be nil")
+
+	assert.NotSame(t, logrus.StandardLogger(), logger, "Expected a new logger")
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel(), "Log level mismatch")
+	assert.IsType(t, &logrus.JSONFormatter{}, logger.Formatter, "Formatter type mismatch")
+	assert.Len(t, logger.Hooks, 7, "Expected the default hook levels")
+	assert.Equal(t, originalLevel, logrus.GetLevel(), "Standard logger should be untouched")
+
+	logger, err = NewLogger(WithFormat("invalid"))
+	require.Error(t, err, "Expected error")
+	assert.Nil(t, logger, "Logger should be nil on error")
+}
+
+func TestConfigureLogger(t *testing.T) {
+	t.Setenv(configKeyLogLevel, "error")
+	t.Setenv(configKeyLogCaller, "true")
+
+	logger := logrus.New()
+	buffer := &bytes.Buffer{}
+
+	err := ConfigureLogger(logger, WithHooks(getStderrHook(buffer)))
+	require.NoError(t, err, "Unexpected error")
+
+	assert.Equal(t, logrus.ErrorLevel, logger.GetLevel(), "Log level mismatch")
+	assert.True(t, logger.ReportCaller, "ReportCaller mismatch")
+	assert.Len(t, getConfiguredHooks(logger), 4, "Expected only the custom hook levels")
+
+	logger.Error("boom")
+	assert.Contains(t, buffer.String(), "boom", "Custom hook should receive the entry")
+
+	err = ConfigureLogger(logger, WithLevel("invalid"))
+	require.Error(t, err, "Expected error")
+	assert.Contains(t, err.Error(), "failed to set log level")
+}
+
+func TestEnvKey(t *testing.T) {
+	testCases := []struct {
+		prefix   string
+		key      string
+		expected string
+	}{
+		{"", configKeyLogLevel, "LOG_LEVEL"},
+		{"AUDIT", configKeyLogLevel, "AUDIT_LOG_LEVEL"},
+		{"APP_", configKeyLogFormat, "APP_LOG_FORMAT"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expected, func(t *testing.T) {
+			assert.Equal(t, tc.expected, envKey(tc.prefix, tc.key))
+		})
+	}
+}
+
+func TestConfigureWithEnvPrefix(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv(configKeyLogLevel, "error")
+	t.Setenv("AUDIT_LOG_LEVEL", "debug")
+	t.Setenv("AUDIT_LOG_FORMAT", "json")
+	t.Setenv("APP_LOG_CALLER", "true")
+
+	auditLogger, err := NewLogger(WithEnvPrefix("AUDIT"))
+	require.NoError(t, err, "Unexpected error")
+	assert.Equal(t, logrus.DebugLevel, auditLogger.GetLevel(), "Log level mismatch")
+	assert.IsType(t, &logrus.JSONFormatter{}, auditLogger.Formatter, "Formatter type mismatch")
+	assert.False(t, auditLogger.ReportCaller, "ReportCaller mismatch")
+
+	appLogger, err := NewLogger(WithEnvPrefix("APP"))
+	require.NoError(t, err, "Unexpected error")
+	assert.Equal(t, logrus.InfoLevel, appLogger.GetLevel(), "Unset prefixed level should use the default")
+	assert.IsType(t, &logrus.TextFormatter{}, appLogger.Formatter, "Formatter type mismatch")
+	assert.True(t, appLogger.ReportCaller, "ReportCaller mismatch")
+
+	appLogger, err = NewLogger(WithEnvPrefix("APP"), WithLevel("warn"))
+	require.NoError(t, err, "Unexpected error")
+	assert.Equal(t, logrus.WarnLevel, appLogger.GetLevel(), "Options should override prefixed env vars")
+
+	t.Setenv("AUDIT_LOG_LEVEL", "invalid")
+
+	_, err = NewLogger(WithEnvPrefix("AUDIT"))
+	require.Error(t, err, "Expected error")
+	assert.Contains(t, err.Error(), "failed to set log level")
+}
+
+func TestParseConfigInvalidEnv(t *testing.T) {
+	t.Setenv("BROKEN_LOG_CALLER", "not-a-bool")
+
+	_, err := parseConfig("BROKEN")
+	require.Error(t, err, "Expected error")
+	assert.Contains(t, err.Error(), "failed to parse log config")
+}
@@ -0,0 +1,141 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// logfmtFormatter formats entries as strict logfmt lines with a stable key
+// order: time, level, msg, func, file and then the fields sorted by key.
+// Unlike logrus.TextFormatter the output never depends on the terminal.
+type logfmtFormatter struct {
+	TimestampFormat  string
+	CallerPrettyfier func(*runtime.Frame) (string, string)
+}
+
+func (f *logfmtFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	timestampFormat := f.TimestampFormat
+	if timestampFormat == "" {
+		timestampFormat = time.RFC3339
+	}
+
+	b := &bytes.Buffer{}
+
+	writeLogfmtPair(b, logrus.FieldKeyTime, entry.Time.Format(timestampFormat))
+	writeLogfmtPair(b, logrus.FieldKeyLevel, entry.Level.String())
+	writeLogfmtPair(b, logrus.FieldKeyMsg, entry.Message)
+
+	if entry.HasCaller() {
+		funcName, fileName := entry.Caller.Function, fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		if f.CallerPrettyfier != nil {
+			funcName, fileName = f.CallerPrettyfier(entry.Caller)
+		}
+
+		if funcName != "" {
+			writeLogfmtPair(b, logrus.FieldKeyFunc, funcName)
+		}
+
+		if fileName != "" {
+			writeLogfmtPair(b, logrus.FieldKeyFile, fileName)
+		}
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		writeLogfmtPair(b, logfmtFieldKey(key), logfmtValue(entry.Data[key]))
+	}
+
+	b.WriteByte('\n')
+
+	return b.Bytes(), nil
+}
+
+// logfmtFieldKey prefixes the fields clashing with the
+// fixed keys the same way logrus does for the other formatters
+func logfmtFieldKey(key string) string {
+	switch key {
+	case logrus.FieldKeyTime,
+		logrus.FieldKeyLevel,
+		logrus.FieldKeyMsg,
+		logrus.FieldKeyFunc,
+		logrus.FieldKeyFile:
+		return "fields." + key
+	default:
+		return key
+	}
+}
+
+func logfmtValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func writeLogfmtPair(b *bytes.Buffer, key string, value string) {
+	if b.Len() > 0 {
+		b.WriteByte(' ')
+	}
+
+	b.WriteString(sanitizeLogfmtKey(key))
+	b.WriteByte('=')
+
+	if !needsLogfmtQuoting(value) {
+		b.WriteString(value)
+
+		return
+	}
+
+	b.WriteString(strconv.Quote(value))
+}
+
+// sanitizeLogfmtKey replaces the characters that would break
+// the key=value parsing and makes sure the key isn't empty
+func sanitizeLogfmtKey(key string) string {
+	if key == "" {
+		return "_"
+	}
+
+	return strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return '_'
+		}
+
+		return r
+	}, key)
+}
+
+func needsLogfmtQuoting(value string) bool {
+	if value == "" {
+		return true
+	}
+
+	for _, r := range value {
+		if r <= ' ' || r == '=' || r == '"' || r == '\\' || r == utf8.RuneError || !unicode.IsPrint(r) {
+			return true
+		}
+	}
+
+	return false
+}
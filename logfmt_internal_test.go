@@ -0,0 +1,128 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogfmtFormatter(t *testing.T) {
+	entryTime := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	caller := &runtime.Frame{
+		Function: "github.com/example/package.TestFunction",
+		File:     "/path/to/file/test.go",
+		Line:     42,
+	}
+
+	testCases := []struct {
+		name     string
+		entry    *logrus.Entry
+		expected string
+	}{
+		{
+			name: "Plain message",
+			entry: &logrus.Entry{
+				Time:    entryTime,
+				Level:   logrus.InfoLevel,
+				Message: "hello",
+				Data:    logrus.Fields{},
+			},
+			expected: "time=2026-01-02T03:04:05Z level=info msg=hello\n",
+		},
+		{
+			name: "Fields are sorted",
+			entry: &logrus.Entry{
+				Time:    entryTime,
+				Level:   logrus.WarnLevel,
+				Message: "sorted",
+				Data:    logrus.Fields{"zeta": 1, "alpha": true, "mid": 1.5},
+			},
+			expected: "time=2026-01-02T03:04:05Z level=warning msg=sorted alpha=true mid=1.5 zeta=1\n",
+		},
+		{
+			name: "Quoting and escaping",
+			entry: &logrus.Entry{
+				Time:    entryTime,
+				Level:   logrus.ErrorLevel,
+				Message: "line one\nline \"two\"",
+				Data: logrus.Fields{
+					"empty":         "",
+					"path":          `C:\temp`,
+					"eq":            "a=b",
+					"bad key=":      "v",
+					logrus.ErrorKey: errors.New("it broke"),
+				},
+			},
+			expected: `time=2026-01-02T03:04:05Z level=error msg="line one\nline \"two\"" ` +
+				`bad_key_=v empty="" eq="a=b" error="it broke" path="C:\\temp"` + "\n",
+		},
+		{
+			name: "Clashing fields are prefixed",
+			entry: &logrus.Entry{
+				Time:    entryTime,
+				Level:   logrus.DebugLevel,
+				Message: "clash",
+				Data:    logrus.Fields{"msg": "other", "level": "x"},
+			},
+			expected: "time=2026-01-02T03:04:05Z level=debug msg=clash fields.level=x fields.msg=other\n",
+		},
+		{
+			name: "Caller",
+			entry: &logrus.Entry{
+				Logger:  &logrus.Logger{ReportCaller: true},
+				Time:    entryTime,
+				Level:   logrus.InfoLevel,
+				Message: "called",
+				Data:    logrus.Fields{},
+				Caller:  caller,
+			},
+			expected: "time=2026-01-02T03:04:05Z level=info msg=called " +
+				"func=github.com/example/package.TestFunction() file=test.go:42\n",
+		},
+	}
+
+	formatter, err := getLogrusFormat(formatLogfmt)
+	require.NoError(t, err)
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			out, err := formatter.Format(tc.entry)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, string(out))
+		})
+	}
+}
+
+func TestLogfmtFormatterWithoutPrettyfier(t *testing.T) {
+	formatter := &logfmtFormatter{TimestampFormat: time.Kitchen}
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Time:    time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC),
+		Level:   logrus.InfoLevel,
+		Message: "raw caller",
+		Data:    logrus.Fields{},
+		Caller:  &runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `time=3:04PM level=info msg="raw caller" func=main.main file=/src/main.go:7`+"\n", string(out))
+}
+
+func TestLogfmtFormatterThroughLogger(t *testing.T) {
+	unsetEnvs(t)
+
+	buffer := &bytes.Buffer{}
+
+	logger, err := NewLogger(WithFormat("logfmt"), WithHooks(getStdoutHook(buffer)))
+	require.NoError(t, err)
+
+	logger.WithField("user", "jane doe").Info("logged in")
+
+	assert.Regexp(t, `^time=\S+ level=info msg="logged in" user="jane doe"\n$`, buffer.String())
+}
@@ -0,0 +1,24 @@
+module github.com/psyb0t/logrus-configurator/logrusgrpc
+
+go 1.25
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/psyb0t/logrus-configurator v0.0.0-20261018091520-319da1b71a46
+	github.com/sirupsen/logrus v1.9.4
+	github.com/stretchr/testify v1.10.0
+	google.golang.org/grpc v1.79.3
+)
+
+require (
+	github.com/BurntSushi/toml v1.5.0 // indirect
+	github.com/davecgh/go-spew v1.1.2-0.20180830191138-d8f796af33cc // indirect
+	github.com/pmezard/go-difflib v1.0.1-0.20181226105442-5d4384ee4fb2 // indirect
+	github.com/psyb0t/gonfiguration v1.4.1 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.40.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
+	google.golang.org/protobuf v1.36.10 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
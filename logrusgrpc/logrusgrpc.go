@@ -0,0 +1,172 @@
+// Package logrusgrpc has the gRPC interceptors passing the calls on to a
+// logrusconfigurator.GRPCLogger. It's a module of its own so the ones
+// not using gRPC don't get grpc-go in their go.mod.
+package logrusgrpc
+
+import (
+	"context"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	logrusconfigurator "github.com/psyb0t/logrus-configurator"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor logs the unary calls the server handles
+func UnaryServerInterceptor(l *logrusconfigurator.GRPCLogger) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req any,
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		l.Log(ctx, getServerCall(ctx, info.FullMethod, start, err))
+
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs the streams the server handles once they're over
+func StreamServerInterceptor(l *logrusconfigurator.GRPCLogger) grpc.StreamServerInterceptor {
+	return func(
+		srv any,
+		stream grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+		err := handler(srv, stream)
+
+		l.Log(stream.Context(), getServerCall(stream.Context(), info.FullMethod, start, err))
+
+		return err
+	}
+}
+
+// UnaryClientInterceptor logs the unary calls the client makes
+func UnaryClientInterceptor(l *logrusconfigurator.GRPCLogger) grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		opts ...grpc.CallOption,
+	) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+
+		l.Log(ctx, getClientCall(ctx, method, cc, start, err))
+
+		return err
+	}
+}
+
+// StreamClientInterceptor logs the streams the client opens once they're
+// over, which is when the response came in for the ones the server doesn't
+// stream and when receiving fails or hits the end for the others
+func StreamClientInterceptor(l *logrusconfigurator.GRPCLogger) grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		start := time.Now()
+
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			l.Log(ctx, getClientCall(ctx, method, cc, start, err))
+
+			return nil, err
+		}
+
+		return &clientStream{
+			ClientStream: stream,
+			serverStream: desc.ServerStreams,
+			done: func(err error) {
+				l.Log(ctx, getClientCall(ctx, method, cc, start, err))
+			},
+		}, nil
+	}
+}
+
+// clientStream calls done once with the error the stream ended with
+type clientStream struct {
+	grpc.ClientStream
+
+	serverStream bool
+	done         func(err error)
+	once         sync.Once
+}
+
+func (s *clientStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err == nil && s.serverStream {
+		return nil
+	}
+
+	s.once.Do(func() {
+		if errors.Is(err, io.EOF) {
+			s.done(nil)
+
+			return
+		}
+
+		s.done(err)
+	})
+
+	return err //nolint:wrapcheck
+}
+
+func getServerCall(ctx context.Context, method string, start time.Time, err error) logrusconfigurator.GRPCCall {
+	call := logrusconfigurator.GRPCCall{
+		Method:   method,
+		Code:     uint32(status.Code(err)),
+		Duration: time.Since(start),
+		Err:      err,
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		call.Peer = p.Addr.String()
+	}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		call.Metadata = md
+	}
+
+	return call
+}
+
+func getClientCall(
+	ctx context.Context,
+	method string,
+	cc *grpc.ClientConn,
+	start time.Time,
+	err error,
+) logrusconfigurator.GRPCCall {
+	call := logrusconfigurator.GRPCCall{
+		Method:   method,
+		Code:     uint32(status.Code(err)),
+		Client:   true,
+		Duration: time.Since(start),
+		Peer:     cc.Target(),
+		Err:      err,
+	}
+
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		call.Metadata = md
+	}
+
+	return call
+}
@@ -0,0 +1,191 @@
+package logrusgrpc
+
+import (
+	"context"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	logrusconfigurator "github.com/psyb0t/logrus-configurator"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const (
+	testBufSize = 1 << 20
+
+	methodCheck = "/grpc.health.v1.Health/Check"
+	methodWatch = "/grpc.health.v1.Health/Watch"
+)
+
+// newTestClient serves the health service over bufconn with the
+// server interceptors logging to server and returns a client with
+// the client interceptors logging to client
+func newTestClient(t *testing.T, server, client *logrus.Logger) healthpb.HealthClient {
+	t.Helper()
+
+	serverLogger := &logrusconfigurator.GRPCLogger{Logger: server, Metadata: []string{"x-tenant"}}
+	clientLogger := &logrusconfigurator.GRPCLogger{Logger: client, Metadata: []string{"x-tenant"}}
+
+	listener := bufconn.Listen(testBufSize)
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(UnaryServerInterceptor(serverLogger)),
+		grpc.StreamInterceptor(StreamServerInterceptor(serverLogger)),
+	)
+
+	healthSrv := health.NewServer()
+	healthSrv.SetServingStatus("orders", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(srv, healthSrv)
+
+	go func() { _ = srv.Serve(listener) }()
+
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient(
+		"passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(UnaryClientInterceptor(clientLogger)),
+		grpc.WithStreamInterceptor(StreamClientInterceptor(clientLogger)),
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return healthpb.NewHealthClient(conn)
+}
+
+func TestUnaryInterceptors(t *testing.T) {
+	server, serverHook := test.NewNullLogger()
+	client, clientHook := test.NewNullLogger()
+
+	healthClient := newTestClient(t, server, client)
+
+	ctx := metadata.AppendToOutgoingContext(t.Context(), "x-tenant", "acme", "authorization", "secret")
+
+	_, err := healthClient.Check(ctx, &healthpb.HealthCheckRequest{Service: "orders"})
+	require.NoError(t, err)
+
+	serverEntry := serverHook.LastEntry()
+	require.NotNil(t, serverEntry)
+	assert.Equal(t, "grpc server call", serverEntry.Message)
+	assert.Equal(t, logrus.InfoLevel, serverEntry.Level)
+	assert.Equal(t, methodCheck, serverEntry.Data["grpc.method"])
+	assert.Equal(t, "OK", serverEntry.Data["grpc.code"])
+	assert.Equal(t, "bufconn", serverEntry.Data["grpc.peer"])
+	assert.Equal(t, "acme", serverEntry.Data["grpc.metadata.x-tenant"])
+	assert.NotContains(t, serverEntry.Data, "grpc.metadata.authorization")
+
+	clientEntry := clientHook.LastEntry()
+	require.NotNil(t, clientEntry)
+	assert.Equal(t, "grpc client call", clientEntry.Message)
+	assert.Equal(t, methodCheck, clientEntry.Data["grpc.method"])
+	assert.Equal(t, "OK", clientEntry.Data["grpc.code"])
+	assert.Equal(t, "passthrough:///bufnet", clientEntry.Data["grpc.peer"])
+	assert.Equal(t, "acme", clientEntry.Data["grpc.metadata.x-tenant"])
+
+	_, err = healthClient.Check(t.Context(), &healthpb.HealthCheckRequest{Service: "payments"})
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	assert.Equal(t, "NotFound", serverHook.LastEntry().Data["grpc.code"])
+	assert.Equal(t, "NotFound", clientHook.LastEntry().Data["grpc.code"])
+	assert.Equal(t, err, clientHook.LastEntry().Data[logrus.ErrorKey])
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	server, serverHook := test.NewNullLogger()
+	client, clientHook := test.NewNullLogger()
+
+	healthClient := newTestClient(t, server, client)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	stream, err := healthClient.Watch(ctx, &healthpb.HealthCheckRequest{Service: "orders"})
+	require.NoError(t, err)
+
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.GetStatus())
+	assert.Empty(t, clientHook.AllEntries(), "Expected the stream to be logged once it's over")
+
+	cancel()
+
+	_, err = stream.Recv()
+	require.Equal(t, codes.Canceled, status.Code(err))
+
+	_, err = stream.Recv()
+	require.Error(t, err)
+
+	require.Len(t, clientHook.AllEntries(), 1, "Expected the stream to be logged once")
+	assert.Equal(t, methodWatch, clientHook.LastEntry().Data["grpc.method"])
+	assert.Equal(t, "Canceled", clientHook.LastEntry().Data["grpc.code"])
+
+	assert.Eventually(t, func() bool {
+		return len(serverHook.AllEntries()) == 1
+	}, 5*time.Second, 10*time.Millisecond, "Expected the server to log the stream once it's over")
+
+	assert.Equal(t, methodWatch, serverHook.LastEntry().Data["grpc.method"])
+	assert.Equal(t, "Canceled", serverHook.LastEntry().Data["grpc.code"])
+}
+
+func TestStreamClientInterceptorEOF(t *testing.T) {
+	client, clientHook := test.NewNullLogger()
+	l := &logrusconfigurator.GRPCLogger{Logger: client}
+
+	interceptor := StreamClientInterceptor(l)
+
+	for _, serverStreams := range []bool{true, false} {
+		clientHook.Reset()
+
+		stream, err := interceptor(
+			t.Context(),
+			&grpc.StreamDesc{ServerStreams: serverStreams},
+			&grpc.ClientConn{},
+			methodWatch,
+			func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+				return &fakeClientStream{msgs: 2}, nil
+			},
+		)
+		require.NoError(t, err)
+
+		for err == nil {
+			err = stream.RecvMsg(nil)
+		}
+
+		require.ErrorIs(t, err, io.EOF)
+		require.Len(t, clientHook.AllEntries(), 1)
+		assert.Equal(t, "OK", clientHook.LastEntry().Data["grpc.code"])
+	}
+}
+
+// fakeClientStream receives msgs messages and then hits the end
+type fakeClientStream struct {
+	grpc.ClientStream
+
+	msgs int
+}
+
+func (s *fakeClientStream) RecvMsg(any) error {
+	if s.msgs == 0 {
+		return io.EOF
+	}
+
+	s.msgs--
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package logrusotel bridges logrusconfigurator.TraceHook to OpenTelemetry.
+// It's a module of its own so the ones not using OpenTelemetry don't get
+// it in their go.mod.
+package logrusotel
+
+import (
+	"context"
+
+	logrusconfigurator "github.com/psyb0t/logrus-configurator"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const severityAttribute = "log.severity"
+
+// NewTraceHook returns a TraceHook taking the trace context from the
+// OpenTelemetry span in the context of the entry and recording the
+// entries at error level and above on that span
+func NewTraceHook() *logrusconfigurator.TraceHook {
+	return &logrusconfigurator.TraceHook{
+		SpanContext: SpanContext,
+		RecordError: RecordError,
+	}
+}
+
+// SpanContext returns the trace context of the OpenTelemetry span in ctx
+func SpanContext(ctx context.Context) (logrusconfigurator.TraceContext, bool) {
+	sc := trace.SpanContextFromContext(ctx)
+
+	return logrusconfigurator.TraceContext{
+		TraceID: sc.TraceID(),
+		SpanID:  sc.SpanID(),
+		Flags:   byte(sc.TraceFlags()),
+	}, sc.IsValid()
+}
+
+// RecordError adds the entry as an event to the OpenTelemetry span in
+// ctx. An entry with an error field is recorded as an exception event
+// of that error, any other as an event named after the message.
+func RecordError(ctx context.Context, entry *logrus.Entry) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	attrs := trace.WithAttributes(attribute.String(severityAttribute, entry.Level.String()))
+
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		span.RecordError(err, attrs)
+
+		return
+	}
+
+	span.AddEvent(entry.Message, attrs)
+}
@@ -0,0 +1,115 @@
+package logrusotel
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	logrusconfigurator "github.com/psyb0t/logrus-configurator"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var errPayment = errors.New("card declined")
+
+// newTestLogger returns a logger running the trace hook in front of a
+// test hook the way WithFieldHooks puts it in front of the outputs
+func newTestLogger() (*logrus.Logger, *test.Hook) {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	logger.AddHook(NewTraceHook())
+
+	return logger, test.NewLocal(logger)
+}
+
+func TestTraceHook(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	logger, hook := newTestLogger()
+
+	ctx, span := provider.Tracer("logrusotel").Start(context.Background(), "checkout")
+	sc := span.SpanContext()
+
+	logger.WithContext(ctx).Info("charging")
+	logger.WithContext(ctx).Error("payment failed")
+	logger.WithContext(ctx).WithError(errPayment).Error("payment failed")
+	logger.WithContext(context.Background()).Error("no span")
+	logger.Warn("no context")
+
+	span.End()
+
+	entries := hook.AllEntries()
+	require.Len(t, entries, 5)
+
+	for _, entry := range entries[:3] {
+		assert.Equal(t, sc.TraceID().String(), entry.Data["trace_id"])
+		assert.Equal(t, sc.SpanID().String(), entry.Data["span_id"])
+		assert.Equal(t, "01", entry.Data["trace_flags"])
+	}
+
+	for _, entry := range entries[3:] {
+		assert.NotContains(t, entry.Data, "trace_id")
+	}
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+
+	events := spans[0].Events()
+	require.Len(t, events, 2)
+
+	assert.Equal(t, "payment failed", events[0].Name)
+	assert.Contains(t, events[0].Attributes, attribute.String(severityAttribute, "error"))
+
+	assert.Equal(t, "exception", events[1].Name)
+	assert.Contains(t, events[1].Attributes, attribute.String("exception.message", errPayment.Error()))
+	assert.Contains(t, events[1].Attributes, attribute.String(severityAttribute, "error"))
+}
+
+func TestSpanContext(t *testing.T) {
+	_, ok := SpanContext(context.Background())
+	assert.False(t, ok)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	tc, ok := SpanContext(trace.ContextWithSpanContext(context.Background(), sc))
+	require.True(t, ok)
+
+	assert.Equal(t, logrusconfigurator.TraceContext{
+		TraceID: [16]byte(sc.TraceID()),
+		SpanID:  [8]byte(sc.SpanID()),
+		Flags:   1,
+	}, tc)
+}
+
+func TestRecordErrorSkipsSpansNotRecording(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(recorder),
+		sdktrace.WithSampler(sdktrace.NeverSample()),
+	)
+
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+
+	logger, hook := newTestLogger()
+
+	ctx, span := provider.Tracer("logrusotel").Start(context.Background(), "checkout")
+	logger.WithContext(ctx).Error("payment failed")
+	span.End()
+
+	require.Len(t, hook.AllEntries(), 1)
+	assert.Empty(t, recorder.Ended())
+}
@@ -0,0 +1,148 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	netDialTimeout  = 5 * time.Second
+	netWriteTimeout = 5 * time.Second
+
+	netMinRedialBackoff = 500 * time.Millisecond
+	netMaxRedialBackoff = 30 * time.Second
+)
+
+// netWriter writes to a network connection and redials it when a write
+// fails, e.g. because the log collector restarted. Failed redials back
+// off and the writes in the meantime fail right away instead of waiting
+// on a dial, which happens outside the lock so it holds up nobody else.
+type netWriter struct {
+	network string
+	address string
+
+	mu       sync.Mutex
+	conn     net.Conn
+	dialing  bool
+	closed   bool
+	backoff  time.Duration
+	redialAt time.Time
+	now      func() time.Time
+}
+
+func newNetWriter(network string, address string) (*netWriter, error) {
+	w := &netWriter{
+		network: network,
+		address: address,
+		now:     time.Now,
+	}
+
+	conn, err := w.dial()
+	if err != nil {
+		return nil, err
+	}
+
+	w.conn = conn
+
+	return w, nil
+}
+
+func (w *netWriter) dial() (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: netDialTimeout}
+
+	conn, err := dialer.DialContext(context.Background(), w.network, w.address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to dial %s %s", w.network, w.address)
+	}
+
+	return conn, nil
+}
+
+func (w *netWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn != nil {
+		n, err := w.write(p)
+		if err == nil {
+			return n, nil
+		}
+
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if err := w.redial(); err != nil {
+		return 0, err
+	}
+
+	return w.write(p)
+}
+
+func (w *netWriter) write(p []byte) (int, error) {
+	_ = w.conn.SetWriteDeadline(w.now().Add(netWriteTimeout))
+
+	n, err := w.conn.Write(p)
+
+	return n, errors.WithStack(err)
+}
+
+// redial dials without holding the lock unless the last dial failed
+// too recently or someone else is already at it. It's called and
+// returns with the lock held.
+func (w *netWriter) redial() error {
+	if w.closed {
+		return errors.Wrapf(net.ErrClosed, "%s %s", w.network, w.address)
+	}
+
+	if w.dialing || w.now().Before(w.redialAt) {
+		return errors.Wrapf(errOutputUnavailable, "%s %s", w.network, w.address)
+	}
+
+	w.dialing = true
+	w.mu.Unlock()
+
+	conn, err := w.dial()
+
+	w.mu.Lock()
+	w.dialing = false
+
+	if err != nil {
+		w.backoff = min(max(2*w.backoff, netMinRedialBackoff), netMaxRedialBackoff)
+		w.redialAt = w.now().Add(w.backoff)
+
+		return err
+	}
+
+	if w.closed {
+		_ = conn.Close()
+
+		return errors.Wrapf(net.ErrClosed, "%s %s", w.network, w.address)
+	}
+
+	w.conn = conn
+	w.backoff = 0
+	w.redialAt = time.Time{}
+
+	return nil
+}
+
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+
+	if w.conn == nil {
+		return nil
+	}
+
+	err := w.conn.Close()
+	w.conn = nil
+
+	return errors.WithStack(err)
+}
@@ -0,0 +1,102 @@
+package logrusconfigurator
+
+import (
+	"bufio"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNetWriterRedials(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	w, err := newNetWriter("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	defer w.Close()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	// simulate the collector going away
+	require.NoError(t, w.conn.Close())
+	require.NoError(t, conn.Close())
+
+	n, err := w.Write([]byte("after reconnect\n"))
+	require.NoError(t, err)
+	assert.Equal(t, len("after reconnect\n"), n)
+
+	conn, err = listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "after reconnect\n", line)
+}
+
+func TestNetWriterClose(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	w, err := newNetWriter("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	require.NoError(t, w.Close())
+	require.NoError(t, w.Close(), "Closing twice should be a no-op")
+
+	require.NoError(t, listener.Close())
+
+	_, err = w.Write([]byte("nobody listening\n"))
+	require.Error(t, err, "Expected dial error")
+}
+
+func TestNewNetWriterDialError(t *testing.T) {
+	_, err := newNetWriter("tcp", "127.0.0.1:1")
+	require.Error(t, err)
+}
+
+func TestNetWriterBacksOffRedials(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	w, err := newNetWriter("tcp", listener.Addr().String())
+	require.NoError(t, err)
+
+	defer w.Close()
+
+	now := time.Now()
+	w.now = func() time.Time { return now }
+
+	// the collector goes away for good
+	require.NoError(t, listener.Close())
+	require.NoError(t, w.conn.Close())
+
+	_, err = w.Write([]byte("lost\n"))
+	require.Error(t, err)
+	require.NotErrorIs(t, err, errOutputUnavailable, "Expected the first write to redial")
+	assert.Equal(t, netMinRedialBackoff, w.backoff)
+
+	_, err = w.Write([]byte("lost\n"))
+	require.ErrorIs(t, err, errOutputUnavailable, "Expected no redial before the backoff is up")
+
+	now = now.Add(netMinRedialBackoff)
+
+	_, err = w.Write([]byte("lost\n"))
+	require.NotErrorIs(t, err, errOutputUnavailable)
+	assert.Equal(t, 2*netMinRedialBackoff, w.backoff)
+
+	w.backoff = netMaxRedialBackoff
+	w.redialAt = now
+
+	_, err = w.Write([]byte("lost\n"))
+	require.Error(t, err)
+	assert.Equal(t, netMaxRedialBackoff, w.backoff)
+}
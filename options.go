@@ -0,0 +1,179 @@
+package logrusconfigurator
+
+import (
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Option overrides a part of the env-driven Config
+type Option func(*Config)
+
+func applyOptions(c *Config, opts ...Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithEnvPrefix namespaces the env vars so multiple loggers can be
+// configured independently, e.g. AUDIT makes AUDIT_LOG_LEVEL the level
+func WithEnvPrefix(prefix string) Option {
+	return func(c *Config) {
+		c.EnvPrefix = prefix
+	}
+}
+
+// WithConfig replaces the whole config, ignoring the environment
+func WithConfig(cfg Config) Option {
+	return func(c *Config) {
+		*c = cfg
+	}
+}
+
+// WithLevel sets the log level (trace, debug, info, warn, error, fatal, panic)
+func WithLevel(lvl string) Option {
+	return func(c *Config) {
+		c.Level = lvl
+	}
+}
+
+// WithPackageLevels overrides the level per component field or
+// caller package with pattern=level pairs, e.g. db=debug,
+// myapp/http=warn or github.com/foo/*=error
+func WithPackageLevels(levels ...string) Option {
+	return func(c *Config) {
+		c.PackageLevels = levels
+	}
+}
+
+// WithSampling caps the entries logged with the same message per
+// level with level=first/thereafter/interval rules, e.g. info=100/10/1s
+func WithSampling(rules ...string) Option {
+	return func(c *Config) {
+		c.Sampling = rules
+	}
+}
+
+// WithDedupe collapses the entries with the same level, message and
+// fields repeated within the window into one with a repeated field
+func WithDedupe(window time.Duration, fields ...string) Option {
+	return func(c *Config) {
+		c.Dedupe = DedupeConfig{Window: window, Fields: fields}
+	}
+}
+
+// WithRateLimit caps the entries logged per level with
+// level=limit/interval rules, e.g. error=100/1s
+func WithRateLimit(rules ...string) Option {
+	return func(c *Config) {
+		c.RateLimit = rules
+	}
+}
+
+// WithRedact hides the sensitive fields and message
+// parts from every output, see RedactConfig
+func WithRedact(redact RedactConfig) Option {
+	return func(c *Config) {
+		c.Redact = redact
+	}
+}
+
+// WithFormat sets the log format (json, text, logfmt, ecs, gelf or pretty)
+func WithFormat(fmt string) Option {
+	return func(c *Config) {
+		c.Format = fmt
+	}
+}
+
+// WithReportCaller enables or disables caller reporting
+func WithReportCaller(reportCaller bool) Option {
+	return func(c *Config) {
+		c.ReportCaller = reportCaller
+	}
+}
+
+// WithColor sets the colors of the pretty format: auto, always or never
+func WithColor(c string) Option {
+	return func(cfg *Config) {
+		cfg.Color = c
+	}
+}
+
+// WithOutputs sets the log destinations: stdout, stderr, file:///path,
+// tcp://host:port, unix:///path, gelf+udp://host:port or gelf+tcp://host:port
+func WithOutputs(outputs ...string) Option {
+	return func(c *Config) {
+		c.Outputs = outputs
+	}
+}
+
+// WithSplit sets how the levels are split between stdout
+// and stderr: default, off or custom
+func WithSplit(s string) Option {
+	return func(c *Config) {
+		c.Split = s
+	}
+}
+
+// WithStderrLevels switches to the custom split sending
+// the given levels to stderr and the rest to stdout
+func WithStderrLevels(levels ...string) Option {
+	return func(c *Config) {
+		c.Split = string(splitCustom)
+		c.StderrLevels = levels
+	}
+}
+
+// WithFile adds a rotating log file output
+func WithFile(file FileConfig) Option {
+	return func(c *Config) {
+		c.File = file
+	}
+}
+
+// WithConfigFile reads the YAML, JSON or TOML config file at the path
+func WithConfigFile(path string) Option {
+	return func(c *Config) {
+		c.ConfigFile = path
+	}
+}
+
+// WithFieldHooks adds hooks that fire before the output hooks so the
+// fields they add get logged, e.g. a TraceHook
+func WithFieldHooks(hooks ...logrus.Hook) Option {
+	return func(c *Config) {
+		c.FieldHooks = hooks
+	}
+}
+
+// WithSlog installs a SlogHandler for the logger as the slog default
+func WithSlog(enabled bool) Option {
+	return func(c *Config) {
+		c.Slog = enabled
+	}
+}
+
+// WithCaptureStdlib sends the log package output to the logger
+// at the given level (trace, debug, info, warn or error)
+func WithCaptureStdlib(lvl string) Option {
+	return func(c *Config) {
+		c.CaptureStdlib = true
+		c.CaptureStdlibLevel = lvl
+	}
+}
+
+// WithAsync writes to the outputs on a goroutine of their own
+// through a bounded queue, see AsyncConfig
+func WithAsync(async AsyncConfig) Option {
+	return func(c *Config) {
+		c.Async = async
+	}
+}
+
+// WithHooks replaces the default stdout/stderr hooks with the given ones.
+// Calling it without any hooks leaves the logger without hooks at all.
+func WithHooks(hooks ...logrus.Hook) Option {
+	return func(c *Config) {
+		c.Hooks = append([]logrus.Hook{}, hooks...)
+	}
+}
@@ -0,0 +1,78 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptions(t *testing.T) {
+	hook := getStdoutHook(&bytes.Buffer{})
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		expected Config
+	}{
+		{
+			name:     "No options",
+			opts:     nil,
+			expected: Config{Level: "info", Format: "text"},
+		},
+		{
+			name: "Level, format and caller",
+			opts: []Option{
+				WithLevel("debug"),
+				WithFormat("json"),
+				WithReportCaller(true),
+			},
+			expected: Config{Level: "debug", Format: "json", ReportCaller: true},
+		},
+		{
+			name:     "Env prefix",
+			opts:     []Option{WithEnvPrefix("AUDIT")},
+			expected: Config{EnvPrefix: "AUDIT", Level: "info", Format: "text"},
+		},
+		{
+			name:     "Split",
+			opts:     []Option{WithSplit("off")},
+			expected: Config{Level: "info", Format: "text", Split: "off"},
+		},
+		{
+			name:     "Stderr levels",
+			opts:     []Option{WithStderrLevels("error", "fatal")},
+			expected: Config{Level: "info", Format: "text", Split: "custom", StderrLevels: []string{"error", "fatal"}},
+		},
+		{
+			name:     "Hooks",
+			opts:     []Option{WithHooks(hook)},
+			expected: Config{Level: "info", Format: "text", Hooks: []logrus.Hook{hook}},
+		},
+		{
+			name:     "No hooks",
+			opts:     []Option{WithHooks()},
+			expected: Config{Level: "info", Format: "text", Hooks: []logrus.Hook{}},
+		},
+		{
+			name: "Whole config then override",
+			opts: []Option{
+				WithConfig(Config{Level: "warn", Format: "json"}),
+				WithLevel("error"),
+			},
+			expected: Config{Level: "error", Format: "json"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cfg := Config{Level: "info", Format: "text"}
+			for _, opt := range tc.opts {
+				opt(&cfg)
+			}
+
+			assert.Equal(t, tc.expected, cfg)
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package logrusconfigurator
+
+import (
+	stderrors "errors"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	outputStdout = "stdout"
+	outputStderr = "stderr"
+)
+
+const (
+	outputSchemeFile = "file"
+	outputSchemeTCP  = "tcp"
+	outputSchemeUnix = "unix"
+
+	outputSchemeGELFUDP = "gelf+udp"
+	outputSchemeGELFTCP = "gelf+tcp"
+)
+
+const logFileMode = 0o640
+
+// defaultOutputs are used when no outputs are configured
+//
+//nolint:gochecknoglobals
+var defaultOutputs = []string{outputStderr, outputStdout}
+
+// loggerOutputs keeps track of the outputs opened for each logger
+// so they can be closed once the logger gets reconfigured
+//
+//nolint:gochecknoglobals
+var (
+	loggerOutputsMu sync.Mutex
+	loggerOutputs   = map[*logrus.Logger][]io.Closer{}
+)
+
+// getConfigHooks returns the custom hooks if there are any or
+// the hooks for the configured outputs and the rotating file
+func getConfigHooks(cfg Config) ([]logrus.Hook, []io.Closer, error) {
+	if cfg.Hooks != nil {
+		return cfg.Hooks, nil, nil
+	}
+
+	stderrLevels, err := getStderrLevels(split(cfg.Split), cfg.StderrLevels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// the default outputs may leave one of them without levels,
+	// one asked for explicitly would never get written to
+	for _, output := range cfg.Outputs {
+		if !hasOutputLevels(output, stderrLevels) {
+			return nil, nil, errors.Wrapf(errInvalidLogOutput, "%s gets no levels with the split", output)
+		}
+	}
+
+	outputs := cfg.Outputs
+	if len(outputs) == 0 {
+		outputs = defaultOutputs
+	}
+
+	hooks, closers, err := getOutputHooks(outputs, stderrLevels)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if cfg.File.Path == "" {
+		return hooks, closers, nil
+	}
+
+	file, err := newRotatingFile(cfg.File)
+	if err != nil {
+		_ = closeOutputs(closers)
+
+		return nil, nil, err
+	}
+
+	return append(hooks, getAllLevelsHook(file)), append(closers, file), nil
+}
+
+func getOutputHooks(outputs []string, stderrLevels []logrus.Level) ([]logrus.Hook, []io.Closer, error) {
+	hooks := make([]logrus.Hook, 0, len(outputs))
+	closers := []io.Closer{}
+
+	for _, output := range outputs {
+		hook, closer, err := getOutputHook(output, stderrLevels)
+		if err != nil {
+			_ = closeOutputs(closers)
+
+			return nil, nil, err
+		}
+
+		hooks = append(hooks, hook)
+
+		if closer != nil {
+			closers = append(closers, closer)
+		}
+	}
+
+	return hooks, closers, nil
+}
+
+// hasOutputLevels tells whether the split leaves any levels to the output
+func hasOutputLevels(output string, stderrLevels []logrus.Level) bool {
+	switch output {
+	case outputStdout:
+		return len(getStdoutLevels(stderrLevels)) > 0
+	case outputStderr:
+		return len(stderrLevels) > 0
+	}
+
+	return true
+}
+
+// getOutputHook returns the hook for the output. stdout and stderr get
+// their share of the levels, the rest of the outputs get all of them.
+func getOutputHook( //nolint:ireturn
+	output string,
+	stderrLevels []logrus.Level,
+) (logrus.Hook, io.Closer, error) {
+	switch output {
+	case outputStdout:
+		return getStdoutLevelsHook(nil, stderrLevels), nil, nil
+	case outputStderr:
+		return getStderrLevelsHook(nil, stderrLevels), nil, nil
+	}
+
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, nil, errors.Wrap(errInvalidLogOutput, output)
+	}
+
+	switch u.Scheme {
+	case outputSchemeGELFUDP, outputSchemeGELFTCP:
+		hook, err := newGELFHook(strings.TrimPrefix(u.Scheme, "gelf+"), u.Host)
+		if err != nil {
+			return nil, nil, errors.Wrapf(err, "failed to open log output %s", output)
+		}
+
+		return hook, hook, nil
+	}
+
+	var w io.WriteCloser
+
+	switch u.Scheme {
+	case outputSchemeFile:
+		w, err = os.OpenFile(u.Host+u.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, logFileMode)
+	case outputSchemeTCP:
+		w, err = newNetWriter(outputSchemeTCP, u.Host)
+	case outputSchemeUnix:
+		w, err = newNetWriter(outputSchemeUnix, u.Host+u.Path)
+	default:
+		return nil, nil, errors.Wrap(errInvalidLogOutput, output)
+	}
+
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "failed to open log output %s", output)
+	}
+
+	return getAllLevelsHook(w), w, nil
+}
+
+// setLoggerOutputs records the outputs opened for the logger
+// and closes the ones it had before
+func setLoggerOutputs(logger *logrus.Logger, closers []io.Closer) error {
+	loggerOutputsMu.Lock()
+	previous := loggerOutputs[logger]
+
+	if len(closers) == 0 {
+		delete(loggerOutputs, logger)
+	} else {
+		loggerOutputs[logger] = closers
+	}
+	loggerOutputsMu.Unlock()
+
+	return closeOutputs(previous)
+}
+
+// closeOutputs closes all of the given outputs
+// and returns the first error encountered
+func closeOutputs(closers []io.Closer) error {
+	var firstErr error
+
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil && firstErr == nil {
+			firstErr = errors.Wrap(err, "failed to close log output")
+		}
+	}
+
+	return firstErr
+}
+
+// outputsHook fires every output on its own and collects their errors,
+// so an output that's down doesn't keep the entry from the other ones
+type outputsHook struct {
+	hooks logrus.LevelHooks
+}
+
+func newOutputsHook(hooks ...logrus.Hook) *outputsHook {
+	h := &outputsHook{hooks: make(logrus.LevelHooks)}
+	for _, hook := range hooks {
+		h.hooks.Add(hook)
+	}
+
+	return h
+}
+
+// getOutputsHook returns the only output as is or all of them in an outputsHook
+func getOutputsHook(hooks []logrus.Hook) logrus.Hook { //nolint:ireturn
+	if len(hooks) == 1 {
+		return hooks[0]
+	}
+
+	return newOutputsHook(hooks...)
+}
+
+func (h *outputsHook) Levels() []logrus.Level {
+	levels := make([]logrus.Level, 0, len(h.hooks))
+	for _, lvl := range logrus.AllLevels {
+		if len(h.hooks[lvl]) > 0 {
+			levels = append(levels, lvl)
+		}
+	}
+
+	return levels
+}
+
+func (h *outputsHook) Fire(entry *logrus.Entry) error {
+	var errs []error
+
+	for _, hook := range h.hooks[entry.Level] {
+		if err := hook.Fire(entry); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return stderrors.Join(errs...)
+}
@@ -0,0 +1,241 @@
+package logrusconfigurator
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/writer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetOutputHook(t *testing.T) {
+	tcpListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer tcpListener.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "log.sock")
+
+	unixListener, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+
+	defer unixListener.Close()
+
+	testCases := []struct {
+		name           string
+		output         string
+		expectedLevels []logrus.Level
+		expectCloser   bool
+		expectError    bool
+	}{
+		{
+			name:           "stdout",
+			output:         "stdout",
+			expectedLevels: []logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel},
+		},
+		{
+			name:   "stderr",
+			output: "stderr",
+			expectedLevels: []logrus.Level{
+				logrus.PanicLevel,
+				logrus.FatalLevel,
+				logrus.ErrorLevel,
+				logrus.WarnLevel,
+			},
+		},
+		{
+			name:           "file",
+			output:         "file://" + filepath.Join(t.TempDir(), "app.log"),
+			expectedLevels: logrus.AllLevels,
+			expectCloser:   true,
+		},
+		{
+			name:           "tcp",
+			output:         "tcp://" + tcpListener.Addr().String(),
+			expectedLevels: logrus.AllLevels,
+			expectCloser:   true,
+		},
+		{
+			name:           "unix",
+			output:         "unix://" + socketPath,
+			expectedLevels: logrus.AllLevels,
+			expectCloser:   true,
+		},
+		{
+			name:        "Unknown scheme",
+			output:      "ftp://example.com/log",
+			expectError: true,
+		},
+		{
+			name:        "Unknown destination",
+			output:      "syslog",
+			expectError: true,
+		},
+		{
+			name:        "Unparsable URL",
+			output:      "file://%zz",
+			expectError: true,
+		},
+		{
+			name:        "Unreachable tcp",
+			output:      "tcp://127.0.0.1:1",
+			expectError: true,
+		},
+		{
+			name:        "Missing directory",
+			output:      "file://" + filepath.Join(t.TempDir(), "nope", "app.log"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			hook, closer, err := getOutputHook(tc.output, defaultStderrLevels)
+			if tc.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			writerHook, ok := hook.(*writer.Hook)
+			require.True(t, ok, "Hook should be of type writer.Hook")
+			assert.Equal(t, tc.expectedLevels, writerHook.LogLevels, "Log levels mismatch")
+
+			if !tc.expectCloser {
+				assert.Nil(t, closer, "Closer should be nil")
+
+				return
+			}
+
+			require.NotNil(t, closer, "Closer should not be nil")
+			require.NoError(t, closer.Close())
+		})
+	}
+}
+
+func TestGetOutputHooksClosesOnError(t *testing.T) {
+	logPath := filepath.Join(t.TempDir(), "app.log")
+
+	hooks, closers, err := getOutputHooks([]string{"stdout", "file://" + logPath, "invalid"}, defaultStderrLevels)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errInvalidLogOutput)
+	assert.Nil(t, hooks)
+	assert.Nil(t, closers)
+}
+
+func TestConfigureWithOutputs(t *testing.T) {
+	unsetEnvs(t)
+
+	defer func() {
+		require.NoError(t, Configure())
+	}()
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+	t.Setenv(configKeyLogOutput, "file://"+logPath+", stderr")
+
+	require.NoError(t, Configure(WithFormat("json")))
+
+	assert.Len(t, getConfiguredHooks(logrus.StandardLogger()), 7, "Expected all hook levels")
+	assert.Len(t, getLevelOutputHooks(logrus.StandardLogger(), logrus.WarnLevel), 2, "Expected file and stderr hooks")
+	assert.Len(t, getLevelOutputHooks(logrus.StandardLogger(), logrus.InfoLevel), 1, "Expected the file hook only")
+
+	logrus.Info("to the file")
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"to the file"`)
+
+	loggerOutputsMu.Lock()
+	assert.Len(t, loggerOutputs[logrus.StandardLogger()], 1, "Expected the file to be tracked")
+	loggerOutputsMu.Unlock()
+
+	require.NoError(t, Configure(WithOutputs("stdout")))
+
+	loggerOutputsMu.Lock()
+	assert.NotContains(t, loggerOutputs, logrus.StandardLogger(), "Expected previous outputs to be released")
+	loggerOutputsMu.Unlock()
+
+	err = Configure(WithOutputs("bogus://nowhere"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set log outputs")
+}
+
+func TestSetLoggerOutputsClosesPrevious(t *testing.T) {
+	logger := logrus.New()
+
+	file, err := os.CreateTemp(t.TempDir(), "app.log")
+	require.NoError(t, err)
+
+	require.NoError(t, setLoggerOutputs(logger, []io.Closer{file}))
+	require.NoError(t, setLoggerOutputs(logger, nil))
+
+	_, err = file.WriteString("closed")
+	require.Error(t, err, "Expected the previous output to be closed")
+
+	require.NoError(t, setLoggerOutputs(logger, []io.Closer{file}))
+	require.Error(t, setLoggerOutputs(logger, nil), "Expected close error for an already closed file")
+}
+
+func TestNetWriterOutput(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	defer listener.Close()
+
+	logger, err := NewLogger(WithOutputs("tcp://"+listener.Addr().String()), WithFormat("json"))
+	require.NoError(t, err)
+
+	defer func() {
+		require.NoError(t, setLoggerOutputs(logger, nil))
+	}()
+
+	conn, err := listener.Accept()
+	require.NoError(t, err)
+
+	defer conn.Close()
+
+	logger.Warn("over the wire")
+
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Contains(t, line, `"msg":"over the wire"`)
+}
+
+func TestOutputsHookFiresEveryOutput(t *testing.T) {
+	buf := &bytes.Buffer{}
+	failing := &writer.Hook{
+		Writer: writerFunc(func([]byte) (int, error) {
+			return 0, io.ErrClosedPipe
+		}),
+		LogLevels: logrus.AllLevels,
+	}
+
+	hook := getOutputsHook([]logrus.Hook{
+		failing,
+		&writer.Hook{Writer: buf, LogLevels: []logrus.Level{logrus.ErrorLevel}},
+	})
+	assert.Equal(t, logrus.AllLevels, hook.Levels())
+
+	entry := logrus.NewEntry(logrus.New())
+	entry.Level = logrus.ErrorLevel
+	entry.Message = "still delivered"
+
+	err := hook.Fire(entry)
+	require.ErrorIs(t, err, io.ErrClosedPipe)
+	assert.Contains(t, buf.String(), "still delivered")
+
+	buf.Reset()
+	entry.Level = logrus.InfoLevel
+
+	require.ErrorIs(t, hook.Fire(entry), io.ErrClosedPipe)
+	assert.Empty(t, buf.String(), "Expected the error output to only get its levels")
+}
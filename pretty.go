@@ -0,0 +1,203 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type color string
+
+const (
+	colorAuto   color = "auto"
+	colorAlways color = "always"
+	colorNever  color = "never"
+)
+
+const envNoColor = "NO_COLOR"
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiDim   = "\x1b[2m"
+	ansiBold  = "\x1b[1m"
+)
+
+const (
+	prettyLevelWidth  = 5
+	prettyFieldIndent = "    "
+)
+
+// prettyLevelColors holds the badge colors as
+// foreground;background ANSI codes for each level
+//
+//nolint:gochecknoglobals
+var prettyLevelColors = map[logrus.Level]string{
+	logrus.TraceLevel: "30;47",
+	logrus.DebugLevel: "30;46",
+	logrus.InfoLevel:  "30;42",
+	logrus.WarnLevel:  "30;43",
+	logrus.ErrorLevel: "97;41",
+	logrus.FatalLevel: "97;45",
+	logrus.PanicLevel: "97;45",
+}
+
+// prettyFormatter is a human friendly formatter for the dev console.
+// Each line gets the time since the formatter was created, a level badge,
+// the message and a dimmed caller, followed by the fields one per line.
+type prettyFormatter struct {
+	Color            bool
+	CallerPrettyfier func(*runtime.Frame) (string, string)
+	start            time.Time
+}
+
+func newPrettyFormatter() *prettyFormatter {
+	return &prettyFormatter{
+		CallerPrettyfier: callerPrettyfier,
+		start:            time.Now(),
+	}
+}
+
+func (f *prettyFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	b := &bytes.Buffer{}
+
+	prefix := fmt.Sprintf("%10s ", "+"+f.elapsed(entry.Time))
+	b.WriteString(f.paint(ansiDim, prefix))
+	b.WriteString(f.badge(entry.Level))
+	b.WriteByte(' ')
+
+	// continuation lines of the message line up with its first line
+	indent := strings.Repeat(" ", len(prefix)+prettyLevelWidth+3) //nolint:mnd
+	b.WriteString(strings.ReplaceAll(entry.Message, "\n", "\n"+indent))
+
+	if entry.HasCaller() {
+		b.WriteString("  ")
+		b.WriteString(f.paint(ansiDim, f.caller(entry.Caller)))
+	}
+
+	b.WriteByte('\n')
+
+	keys := make([]string, 0, len(entry.Data))
+	for key := range entry.Data {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		f.writeField(b, key, entry.Data[key])
+	}
+
+	return b.Bytes(), nil
+}
+
+func (f *prettyFormatter) elapsed(t time.Time) string {
+	if t.IsZero() {
+		t = time.Now()
+	}
+
+	return fmt.Sprintf("%.3fs", t.Sub(f.start).Seconds())
+}
+
+func (f *prettyFormatter) badge(lvl logrus.Level) string {
+	name := strings.ToUpper(lvl.String())
+	if lvl == logrus.WarnLevel {
+		name = "WARN"
+	}
+
+	name = fmt.Sprintf("%-*s", prettyLevelWidth, name)
+	if !f.Color {
+		return "[" + name + "]"
+	}
+
+	return "\x1b[" + prettyLevelColors[lvl] + "m " + name + " " + ansiReset
+}
+
+func (f *prettyFormatter) caller(frame *runtime.Frame) string {
+	funcName, fileName := frame.Function, fmt.Sprintf("%s:%d", frame.File, frame.Line)
+	if f.CallerPrettyfier != nil {
+		funcName, fileName = f.CallerPrettyfier(frame)
+	}
+
+	return strings.TrimSpace(fileName + " " + funcName)
+}
+
+func (f *prettyFormatter) writeField(b *bytes.Buffer, key string, value any) {
+	b.WriteString(prettyFieldIndent)
+	b.WriteString(f.paint(ansiBold, key))
+	b.WriteByte('=')
+
+	var stackTracer interface{ StackTrace() errors.StackTrace }
+
+	err, isErr := value.(error)
+	if isErr && errors.As(err, &stackTracer) {
+		b.WriteString(err.Error())
+		b.WriteByte('\n')
+
+		stack := strings.TrimPrefix(fmt.Sprintf("%+v", stackTracer.StackTrace()), "\n")
+		for line := range strings.SplitSeq(stack, "\n") {
+			b.WriteString(prettyFieldIndent + prettyFieldIndent)
+			b.WriteString(f.paint(ansiDim, strings.TrimSpace(line)))
+			b.WriteByte('\n')
+		}
+
+		return
+	}
+
+	text := fmt.Sprint(value)
+	if isErr {
+		text = err.Error()
+	}
+
+	b.WriteString(strings.ReplaceAll(text, "\n", "\n"+prettyFieldIndent+prettyFieldIndent))
+	b.WriteByte('\n')
+}
+
+func (f *prettyFormatter) paint(code string, s string) string {
+	if !f.Color {
+		return s
+	}
+
+	return code + s + ansiReset
+}
+
+// isColorEnabled resolves the color mode. Auto colors when stdout is
+// a terminal and NO_COLOR isn't set, always and never do what they say.
+func isColorEnabled(c color) (bool, error) {
+	switch c {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	case colorAuto, "":
+		if os.Getenv(envNoColor) != "" {
+			return false, nil
+		}
+
+		return isTerminal(os.Stdout), nil
+	default:
+		return false, errors.Wrap(errInvalidLogColor, string(c))
+	}
+}
+
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// setFormatterColor turns the colors of the pretty formatter on or off
+func setFormatterColor(formatter logrus.Formatter, enabled bool) {
+	if f, ok := formatter.(*prettyFormatter); ok {
+		f.Color = enabled
+	}
+}
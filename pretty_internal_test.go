@@ -0,0 +1,172 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPrettyFormatter(t *testing.T) {
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	formatter := &prettyFormatter{CallerPrettyfier: callerPrettyfier, start: start}
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Time:    start.Add(1234 * time.Millisecond),
+		Level:   logrus.WarnLevel,
+		Message: "first line\nsecond line",
+		Data: logrus.Fields{
+			"user":  "jane",
+			"multi": "a\nb",
+			"plain": stdError("plain error"),
+		},
+		Caller: &runtime.Frame{
+			Function: "main.main",
+			File:     "/src/main.go",
+			Line:     7,
+		},
+	})
+	require.NoError(t, err)
+
+	expected := "   +1.234s [WARN ] first line\n" +
+		"                   second line  main.go:7 main.main()\n" +
+		"    multi=a\n" +
+		"        b\n" +
+		"    plain=plain error\n" +
+		"    user=jane\n"
+	assert.Equal(t, expected, string(out))
+}
+
+func TestPrettyFormatterBadges(t *testing.T) {
+	formatter := &prettyFormatter{start: time.Now()}
+
+	testCases := []struct {
+		level    logrus.Level
+		expected string
+	}{
+		{logrus.TraceLevel, "[TRACE]"},
+		{logrus.DebugLevel, "[DEBUG]"},
+		{logrus.InfoLevel, "[INFO ]"},
+		{logrus.WarnLevel, "[WARN ]"},
+		{logrus.ErrorLevel, "[ERROR]"},
+		{logrus.FatalLevel, "[FATAL]"},
+		{logrus.PanicLevel, "[PANIC]"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level.String(), func(t *testing.T) {
+			assert.Equal(t, tc.expected, formatter.badge(tc.level))
+		})
+	}
+}
+
+func TestPrettyFormatterColor(t *testing.T) {
+	formatter := &prettyFormatter{Color: true, start: time.Now()}
+
+	out, err := formatter.Format(&logrus.Entry{
+		Logger:  &logrus.Logger{ReportCaller: true},
+		Level:   logrus.ErrorLevel,
+		Message: "colored",
+		Data:    logrus.Fields{"key": "value"},
+		Caller:  &runtime.Frame{Function: "main.main", File: "/src/main.go", Line: 7},
+	})
+	require.NoError(t, err)
+
+	line := string(out)
+	assert.Contains(t, line, "\x1b[97;41m ERROR \x1b[0m", "Expected a colored badge")
+	assert.Contains(t, line, ansiDim+"/src/main.go:7 main.main"+ansiReset, "Expected a dimmed caller")
+	assert.Contains(t, line, ansiBold+"key"+ansiReset+"=value", "Expected a bold field key")
+}
+
+func TestPrettyFormatterStackTrace(t *testing.T) {
+	formatter := newPrettyFormatter()
+
+	out, err := formatter.Format(&logrus.Entry{
+		Level:   logrus.ErrorLevel,
+		Message: "failed",
+		Data:    logrus.Fields{logrus.ErrorKey: errors.New("with stack")},
+	})
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(string(out), "\n"), "\n")
+	require.Greater(t, len(lines), 3, "Expected the stack trace on its own lines")
+	assert.Equal(t, "    error=with stack", lines[1])
+	assert.True(t, strings.HasPrefix(lines[2], "        github.com/psyb0t/logrus-configurator.TestPrettyFormatterStackTrace"))
+	assert.True(t, strings.HasPrefix(lines[3], "        "), "Stack lines should be indented")
+}
+
+func TestIsColorEnabled(t *testing.T) {
+	testCases := []struct {
+		name        string
+		color       color
+		noColor     string
+		expected    bool
+		expectError bool
+	}{
+		{"Always", colorAlways, "", true, false},
+		{"Always beats NO_COLOR", colorAlways, "1", true, false},
+		{"Never", colorNever, "", false, false},
+		{"Auto with NO_COLOR", colorAuto, "1", false, false},
+		{"Auto without a terminal", colorAuto, "", false, false},
+		{"Empty means auto", "", "1", false, false},
+		{"Invalid", "rainbow", "", false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(envNoColor, tc.noColor)
+
+			enabled, err := isColorEnabled(tc.color)
+			if tc.expectError {
+				require.ErrorIs(t, err, errInvalidLogColor)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, enabled)
+		})
+	}
+}
+
+func TestConfigureWithPrettyFormat(t *testing.T) {
+	unsetEnvs(t)
+
+	buffer := &bytes.Buffer{}
+
+	logger, err := NewLogger(WithFormat("pretty"), WithColor("always"), WithHooks(getStdoutHook(buffer)))
+	require.NoError(t, err)
+
+	formatter, ok := logger.Formatter.(*prettyFormatter)
+	require.True(t, ok, "Expected the pretty formatter")
+	assert.True(t, formatter.Color, "Expected colors to be forced on")
+
+	logger.Info("pretty")
+	assert.Contains(t, buffer.String(), "\x1b[30;42m INFO  \x1b[0m pretty")
+
+	t.Setenv(configKeyLogColor, "never")
+
+	logger, err = NewLogger(WithFormat("pretty"))
+	require.NoError(t, err)
+
+	formatter, ok = logger.Formatter.(*prettyFormatter)
+	require.True(t, ok, "Expected the pretty formatter")
+	assert.False(t, formatter.Color, "Expected colors to be off")
+
+	_, err = NewLogger(WithColor("rainbow"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to set log color")
+}
+
+type stdError string
+
+func (e stdError) Error() string {
+	return string(e)
+}
@@ -0,0 +1,138 @@
+package logrusconfigurator
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const rateLimitedField = "rate_limited"
+
+// tokenBucket lets through up to limit entries at once and
+// refills at limit entries per interval
+type tokenBucket struct {
+	limit    float64
+	interval time.Duration
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	limited int
+}
+
+// getRateLimits parses the level=limit/interval rules
+func getRateLimits(rateLimits []string) (map[logrus.Level]*tokenBucket, error) {
+	buckets := make(map[logrus.Level]*tokenBucket, len(rateLimits))
+
+	for _, r := range rateLimits {
+		lvl, spec, ok := strings.Cut(strings.TrimSpace(r), "=")
+		if !ok {
+			return nil, errors.Wrap(errInvalidLogRateLimit, r)
+		}
+
+		logrusLevel, err := getLogrusLevel(level(strings.TrimSpace(lvl)))
+		if err != nil {
+			return nil, errors.Wrap(err, r)
+		}
+
+		limitSpec, intervalSpec, ok := strings.Cut(strings.TrimSpace(spec), "/")
+		if !ok {
+			return nil, errors.Wrap(errInvalidLogRateLimit, r)
+		}
+
+		limit, err := strconv.ParseUint(limitSpec, 10, 64)
+		if err != nil || limit == 0 {
+			return nil, errors.Wrap(errInvalidLogRateLimit, r)
+		}
+
+		interval, err := time.ParseDuration(intervalSpec)
+		if err != nil || interval <= 0 {
+			return nil, errors.Wrap(errInvalidLogRateLimit, r)
+		}
+
+		buckets[logrusLevel] = &tokenBucket{
+			limit:    float64(limit),
+			interval: interval,
+			tokens:   float64(limit),
+		}
+	}
+
+	return buckets, nil
+}
+
+// take takes a token if there's one and returns how many
+// entries were limited since the last one that got through
+func (b *tokenBucket) take(now time.Time) (bool, int) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.last.IsZero() {
+		refill := now.Sub(b.last).Seconds() / b.interval.Seconds() * b.limit
+		b.tokens = min(b.limit, b.tokens+refill)
+	}
+
+	b.last = now
+
+	if b.tokens < 1 {
+		b.limited++
+
+		return false, 0
+	}
+
+	b.tokens--
+
+	limited := b.limited
+	b.limited = 0
+
+	return true, limited
+}
+
+// rateLimitHook sits in front of the output hooks and drops the entries
+// over the limit of their level. The next entry that gets through says
+// how many were dropped.
+type rateLimitHook struct {
+	buckets map[logrus.Level]*tokenBucket
+	hooks   logrus.LevelHooks
+	now     func() time.Time
+}
+
+func newRateLimitHook(buckets map[logrus.Level]*tokenBucket, hooks ...logrus.Hook) *rateLimitHook {
+	h := &rateLimitHook{
+		buckets: buckets,
+		hooks:   make(logrus.LevelHooks),
+		now:     time.Now,
+	}
+
+	for _, hook := range hooks {
+		h.hooks.Add(hook)
+	}
+
+	return h
+}
+
+func (h *rateLimitHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *rateLimitHook) Fire(entry *logrus.Entry) error {
+	if bucket, ok := h.buckets[entry.Level]; ok {
+		allowed, limited := bucket.take(h.now())
+		if !allowed {
+			return nil
+		}
+
+		if limited > 0 {
+			if entry.Data == nil {
+				entry.Data = logrus.Fields{}
+			}
+
+			entry.Data[rateLimitedField] = limited
+		}
+	}
+
+	return h.hooks.Fire(entry.Level, entry) //nolint:wrapcheck
+}
@@ -0,0 +1,133 @@
+package logrusconfigurator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetRateLimits(t *testing.T) {
+	testCases := []struct {
+		name          string
+		rateLimits    []string
+		expected      map[logrus.Level]time.Duration
+		expectedError string
+	}{
+		{
+			name:     "None",
+			expected: map[logrus.Level]time.Duration{},
+		},
+		{
+			name:       "Rules",
+			rateLimits: []string{"error=100/1s", " warn = 10/1m "},
+			expected:   map[logrus.Level]time.Duration{logrus.ErrorLevel: time.Second, logrus.WarnLevel: time.Minute},
+		},
+		{
+			name:          "Missing limit",
+			rateLimits:    []string{"error"},
+			expectedError: "error: invalid log rate limit rule",
+		},
+		{
+			name:          "Invalid level",
+			rateLimits:    []string{"loud=1/1s"},
+			expectedError: "loud=1/1s: loud: invalid log level",
+		},
+		{
+			name:          "Missing interval",
+			rateLimits:    []string{"error=100"},
+			expectedError: "error=100: invalid log rate limit rule",
+		},
+		{
+			name:          "Zero limit",
+			rateLimits:    []string{"error=0/1s"},
+			expectedError: "error=0/1s: invalid log rate limit rule",
+		},
+		{
+			name:          "Invalid interval",
+			rateLimits:    []string{"error=1/soon"},
+			expectedError: "error=1/soon: invalid log rate limit rule",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buckets, err := getRateLimits(tc.rateLimits)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			intervals := map[logrus.Level]time.Duration{}
+			for lvl, bucket := range buckets {
+				intervals[lvl] = bucket.interval
+			}
+
+			assert.Equal(t, tc.expected, intervals)
+		})
+	}
+}
+
+func TestRateLimitHook(t *testing.T) {
+	buckets, err := getRateLimits([]string{"error=2/1s"})
+	require.NoError(t, err)
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	now := time.Now()
+
+	h := newRateLimitHook(buckets, hook)
+	h.now = func() time.Time { return now }
+
+	fire := func(lvl logrus.Level) {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: lvl, Message: lvl.String()}))
+	}
+
+	for range 5 {
+		fire(logrus.ErrorLevel)
+		fire(logrus.InfoLevel)
+	}
+
+	assert.Equal(t, []string{"error", "info", "error", "info", "info", "info", "info"}, hook.getMessages())
+
+	// half the interval refills one token
+	now = now.Add(500 * time.Millisecond)
+	fire(logrus.ErrorLevel)
+	fire(logrus.ErrorLevel)
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	require.Len(t, hook.data, 8)
+	assert.Equal(t, logrus.Fields{rateLimitedField: 3}, hook.data[7], "Expected the limited entries to be counted")
+}
+
+func TestConfigureRateLimit(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv(configKeyLogRateLimit, "error=1/1h")
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	logger, err := NewLogger(WithHooks(hook))
+	require.NoError(t, err)
+
+	require.Len(t, getConfiguredHooks(logger)[logrus.ErrorLevel], 1)
+
+	_, ok := getConfiguredHooks(logger)[logrus.ErrorLevel][0].(*rateLimitHook)
+	require.True(t, ok, "Expected the rate limit hook in front of the outputs")
+
+	logger.Error("db down")
+	logger.Error("db down")
+	logger.Warn("db slow")
+	assert.Equal(t, []string{"db down", "db slow"}, hook.getMessages())
+
+	_, err = NewLogger(WithRateLimit("error=1"))
+	require.EqualError(t, err, "failed to set log rate limit: error=1: invalid log rate limit rule")
+}
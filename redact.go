@@ -0,0 +1,280 @@
+package logrusconfigurator
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	stderrors "errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type redactMode string
+
+const (
+	// redactModeMask replaces the sensitive data with a placeholder
+	redactModeMask redactMode = "mask"
+	// redactModeHash replaces the sensitive data with a short keyed
+	// hash of it so the same values can still be told apart
+	redactModeHash redactMode = "hash"
+	// redactModeDrop removes the sensitive fields and message parts
+	redactModeDrop redactMode = "drop"
+)
+
+const (
+	redactRuleCreditCard = "credit-card"
+	redactRuleJWT        = "jwt"
+	redactRuleEmail      = "email"
+	redactRuleAWSKey     = "aws-key"
+)
+
+const (
+	redactedPlaceholder = "[REDACTED]"
+	redactHashPrefix    = "hmac:"
+	redactHashSize      = 8
+	redactHashKeySize   = 32
+)
+
+// defaultRedactFields are redacted when no fields are configured
+//
+//nolint:gochecknoglobals
+var defaultRedactFields = []string{"password", "token", "authorization"}
+
+// processRedactHashKey is the key of the hashes when none is configured,
+// random so nobody can hash guesses to match them up with the logs
+//
+//nolint:gochecknoglobals
+var processRedactHashKey = sync.OnceValue(func() []byte {
+	key := make([]byte, redactHashKeySize)
+	_, _ = rand.Read(key)
+
+	return key
+})
+
+// redactRules are the patterns of the sensitive data in messages
+//
+//nolint:gochecknoglobals
+var redactRules = map[string]*regexp.Regexp{
+	redactRuleCreditCard: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+	redactRuleJWT:        regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	redactRuleEmail:      regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	redactRuleAWSKey:     regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+}
+
+// redactRuleNames keeps the message rules in the order they're applied
+//
+//nolint:gochecknoglobals
+var redactRuleNames = []string{redactRuleJWT, redactRuleAWSKey, redactRuleEmail, redactRuleCreditCard}
+
+// RedactConfig configures the redaction of sensitive data
+type RedactConfig struct {
+	// Enabled redacts the fields and messages before they get formatted
+	Enabled bool
+	// Fields are the names of the fields to redact, matched regardless
+	// of case and against the last part of dotted names like the ones
+	// of slog groups, password, token and authorization when empty
+	Fields []string
+	// Messages are the rules for the message and the string fields:
+	// credit-card, jwt, email and aws-key, all of them when empty
+	Messages []string
+	// Mode is mask (the default), hash or drop
+	Mode string
+	// HashKey is the HMAC key of the hash mode. The hashes only match
+	// within one process when it's empty, set it to match them across
+	// processes and restarts.
+	HashKey string
+}
+
+// redactHook redacts the fields and the messages of the entries in place.
+// It fires after the field hooks and before the output hooks so nothing
+// sensitive makes it to a formatter, whichever output it's for.
+type redactHook struct {
+	fields  map[string]struct{}
+	rules   []string
+	mode    redactMode
+	hashKey []byte
+}
+
+func newRedactHook(cfg RedactConfig) (*redactHook, error) {
+	mode := redactMode(cfg.Mode)
+	switch mode {
+	case "":
+		mode = redactModeMask
+	case redactModeMask, redactModeHash, redactModeDrop:
+	default:
+		return nil, errors.Wrapf(errInvalidLogRedact, "mode %s", cfg.Mode)
+	}
+
+	fields := cfg.Fields
+	if len(fields) == 0 {
+		fields = defaultRedactFields
+	}
+
+	ruleNames := cfg.Messages
+	if len(ruleNames) == 0 {
+		ruleNames = redactRuleNames
+	}
+
+	enabledRules := make(map[string]struct{}, len(ruleNames))
+	for _, name := range ruleNames {
+		name = strings.TrimSpace(name)
+		if _, ok := redactRules[name]; !ok {
+			return nil, errors.Wrapf(errInvalidLogRedact, "message rule %s", name)
+		}
+
+		enabledRules[name] = struct{}{}
+	}
+
+	h := &redactHook{
+		fields:  make(map[string]struct{}, len(fields)),
+		rules:   make([]string, 0, len(enabledRules)),
+		mode:    mode,
+		hashKey: []byte(cfg.HashKey),
+	}
+
+	if len(h.hashKey) == 0 {
+		h.hashKey = processRedactHashKey()
+	}
+
+	for _, field := range fields {
+		h.fields[strings.ToLower(strings.TrimSpace(field))] = struct{}{}
+	}
+
+	for _, name := range redactRuleNames {
+		if _, ok := enabledRules[name]; ok {
+			h.rules = append(h.rules, name)
+		}
+	}
+
+	return h, nil
+}
+
+func (h *redactHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *redactHook) Fire(entry *logrus.Entry) error {
+	entry.Message = h.redactString(entry.Message)
+
+	for key, value := range entry.Data {
+		if h.isRedactedField(key) {
+			if h.mode == redactModeDrop {
+				delete(entry.Data, key)
+
+				continue
+			}
+
+			entry.Data[key] = h.replace(fmt.Sprint(value))
+
+			continue
+		}
+
+		entry.Data[key] = h.redactValue(value)
+	}
+
+	return nil
+}
+
+// isRedactedField matches the whole key and its last dotted part, so
+// the fields of slog groups and gRPC metadata get redacted too
+func (h *redactHook) isRedactedField(key string) bool {
+	key = strings.ToLower(key)
+	if _, ok := h.fields[key]; ok {
+		return true
+	}
+
+	i := strings.LastIndexByte(key, '.')
+	if i < 0 {
+		return false
+	}
+
+	_, ok := h.fields[key[i+1:]]
+
+	return ok
+}
+
+// redactValue redacts the strings and the text of the errors and the
+// fmt.Stringers, which turn into a string or an error if that changes it
+func (h *redactHook) redactValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		return h.redactString(v)
+	case error, fmt.Stringer:
+		s := fmt.Sprint(v)
+
+		redacted := h.redactString(s)
+		if redacted == s {
+			return value
+		}
+
+		if _, ok := v.(error); ok {
+			return stderrors.New(redacted) //nolint:err113
+		}
+
+		return redacted
+	default:
+		return value
+	}
+}
+
+// redactString redacts the parts of s matched by the message rules
+func (h *redactHook) redactString(s string) string {
+	for _, name := range h.rules {
+		s = redactRules[name].ReplaceAllStringFunc(s, func(match string) string {
+			if name == redactRuleCreditCard && !isLuhnValid(match) {
+				return match
+			}
+
+			return h.replace(match)
+		})
+	}
+
+	return s
+}
+
+func (h *redactHook) replace(s string) string {
+	switch h.mode {
+	case redactModeHash:
+		mac := hmac.New(sha256.New, h.hashKey)
+		mac.Write([]byte(s))
+
+		return redactHashPrefix + hex.EncodeToString(mac.Sum(nil)[:redactHashSize])
+	case redactModeDrop:
+		return ""
+	default:
+		return redactedPlaceholder
+	}
+}
+
+// isLuhnValid tells card numbers apart from
+// other long numbers like ids and timestamps
+func isLuhnValid(number string) bool {
+	sum := 0
+	double := false
+
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			continue
+		}
+
+		digit := int(c - '0')
+		if double {
+			digit *= 2
+			if digit > 9 { //nolint:mnd
+				digit -= 9
+			}
+		}
+
+		sum += digit
+		double = !double
+	}
+
+	return sum%10 == 0
+}
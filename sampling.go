@@ -0,0 +1,220 @@
+package logrusconfigurator
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const samplingSummaryMessage = "logrus-configurator: log entries sampled out"
+
+// samplingReportInterval is how often the sampled out entries get counted
+//
+//nolint:gochecknoglobals
+var samplingReportInterval = 10 * time.Second
+
+// samplingRule is a level=first/thereafter/interval rule from LOG_SAMPLING.
+// Within each interval the first entries with the same message get logged
+// and after that only every thereafter-th one, or none when it's 0.
+type samplingRule struct {
+	level      logrus.Level
+	first      uint64
+	thereafter uint64
+	interval   time.Duration
+}
+
+// getSamplingRules parses the level=first/thereafter/interval rules
+func getSamplingRules(sampling []string) (map[logrus.Level]samplingRule, error) {
+	rules := make(map[logrus.Level]samplingRule, len(sampling))
+
+	for _, s := range sampling {
+		lvl, spec, ok := strings.Cut(strings.TrimSpace(s), "=")
+		if !ok {
+			return nil, errors.Wrap(errInvalidLogSampling, s)
+		}
+
+		logrusLevel, err := getLogrusLevel(level(strings.TrimSpace(lvl)))
+		if err != nil {
+			return nil, errors.Wrap(err, s)
+		}
+
+		parts := strings.Split(strings.TrimSpace(spec), "/")
+		if len(parts) != 3 { //nolint:mnd
+			return nil, errors.Wrap(errInvalidLogSampling, s)
+		}
+
+		first, err := strconv.ParseUint(parts[0], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidLogSampling, s)
+		}
+
+		thereafter, err := strconv.ParseUint(parts[1], 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(errInvalidLogSampling, s)
+		}
+
+		interval, err := time.ParseDuration(parts[2])
+		if err != nil || interval <= 0 {
+			return nil, errors.Wrap(errInvalidLogSampling, s)
+		}
+
+		rules[logrusLevel] = samplingRule{
+			level:      logrusLevel,
+			first:      first,
+			thereafter: thereafter,
+			interval:   interval,
+		}
+	}
+
+	return rules, nil
+}
+
+// samplingCounter counts the entries of one level per message
+// within the current interval and the ones sampled out
+type samplingCounter struct {
+	rule samplingRule
+
+	mu         sync.Mutex
+	start      time.Time
+	counts     map[string]uint64
+	sampledOut uint64
+}
+
+func (c *samplingCounter) sample(message string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if now.Sub(c.start) >= c.rule.interval {
+		c.start = now
+		clear(c.counts)
+	}
+
+	n := c.counts[message] + 1
+	c.counts[message] = n
+
+	if n <= c.rule.first || (c.rule.thereafter > 0 && (n-c.rule.first)%c.rule.thereafter == 0) {
+		return true
+	}
+
+	c.sampledOut++
+
+	return false
+}
+
+func (c *samplingCounter) takeSampledOut() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := c.sampledOut
+	c.sampledOut = 0
+
+	return n
+}
+
+// samplingHook sits in front of the output hooks and drops the entries
+// sampled out by the rule of their level. Every now and then it logs
+// how many entries each level lost.
+type samplingHook struct {
+	logger   *logrus.Logger
+	hooks    logrus.LevelHooks
+	counters map[logrus.Level]*samplingCounter
+	now      func() time.Time
+
+	stop      chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newSamplingHook(
+	logger *logrus.Logger,
+	rules map[logrus.Level]samplingRule,
+	hooks ...logrus.Hook,
+) *samplingHook {
+	h := &samplingHook{
+		logger:   logger,
+		hooks:    make(logrus.LevelHooks),
+		counters: make(map[logrus.Level]*samplingCounter, len(rules)),
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+
+	for _, hook := range hooks {
+		h.hooks.Add(hook)
+	}
+
+	for lvl, rule := range rules {
+		h.counters[lvl] = &samplingCounter{rule: rule, counts: map[string]uint64{}}
+	}
+
+	go h.run()
+
+	return h
+}
+
+func (h *samplingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *samplingHook) Fire(entry *logrus.Entry) error {
+	if counter, ok := h.counters[entry.Level]; ok && !counter.sample(entry.Message, h.now()) {
+		return nil
+	}
+
+	return h.hooks.Fire(entry.Level, entry) //nolint:wrapcheck
+}
+
+func (h *samplingHook) run() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(samplingReportInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			h.report()
+		case <-h.stop:
+			h.report()
+
+			return
+		}
+	}
+}
+
+// report logs a summary entry for each level that had entries sampled out
+func (h *samplingHook) report() {
+	for lvl, counter := range h.counters {
+		n := counter.takeSampledOut()
+		if n == 0 {
+			continue
+		}
+
+		entry := &logrus.Entry{
+			Logger:  h.logger,
+			Data:    logrus.Fields{"sampled_out": n},
+			Time:    h.now(),
+			Level:   lvl,
+			Message: samplingSummaryMessage,
+		}
+
+		if err := h.hooks.Fire(lvl, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Failed to fire hook: %v\n", err)
+		}
+	}
+}
+
+// Close stops the reporting after a last summary
+func (h *samplingHook) Close() error {
+	h.closeOnce.Do(func() { close(h.stop) })
+
+	<-h.done
+
+	return nil
+}
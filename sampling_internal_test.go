@@ -0,0 +1,201 @@
+package logrusconfigurator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSamplingRules(t *testing.T) {
+	testCases := []struct {
+		name          string
+		sampling      []string
+		expected      map[logrus.Level]samplingRule
+		expectedError string
+	}{
+		{
+			name:     "None",
+			expected: map[logrus.Level]samplingRule{},
+		},
+		{
+			name:     "Rules",
+			sampling: []string{"info=100/10/1s", " debug = 10/0/1m "},
+			expected: map[logrus.Level]samplingRule{
+				logrus.InfoLevel:  {level: logrus.InfoLevel, first: 100, thereafter: 10, interval: time.Second},
+				logrus.DebugLevel: {level: logrus.DebugLevel, first: 10, thereafter: 0, interval: time.Minute},
+			},
+		},
+		{
+			name:          "Missing spec",
+			sampling:      []string{"info"},
+			expectedError: "info: invalid log sampling rule",
+		},
+		{
+			name:          "Invalid level",
+			sampling:      []string{"loud=1/1/1s"},
+			expectedError: "loud=1/1/1s: loud: invalid log level",
+		},
+		{
+			name:          "Missing interval",
+			sampling:      []string{"info=100/10"},
+			expectedError: "info=100/10: invalid log sampling rule",
+		},
+		{
+			name:          "Negative first",
+			sampling:      []string{"info=-1/10/1s"},
+			expectedError: "info=-1/10/1s: invalid log sampling rule",
+		},
+		{
+			name:          "Invalid thereafter",
+			sampling:      []string{"info=1/x/1s"},
+			expectedError: "info=1/x/1s: invalid log sampling rule",
+		},
+		{
+			name:          "Zero interval",
+			sampling:      []string{"info=1/1/0s"},
+			expectedError: "info=1/1/0s: invalid log sampling rule",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rules, err := getSamplingRules(tc.sampling)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rules)
+		})
+	}
+}
+
+func TestSamplingHook(t *testing.T) {
+	rules, err := getSamplingRules([]string{"info=2/3/1s", "warn=1/0/1s"})
+	require.NoError(t, err)
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	now := time.Now()
+
+	h := newSamplingHook(logrus.New(), rules, hook)
+	h.now = func() time.Time { return now }
+
+	fire := func(lvl logrus.Level, msg string) {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: lvl, Message: msg}))
+	}
+
+	for i := range 9 {
+		fire(logrus.InfoLevel, "hot")
+		fire(logrus.WarnLevel, "hot")
+		fire(logrus.ErrorLevel, fmt.Sprintf("unsampled %d", i))
+	}
+
+	fire(logrus.InfoLevel, "cold")
+
+	// the counts start over once the interval is up
+	now = now.Add(time.Second)
+	fire(logrus.WarnLevel, "hot")
+
+	expected := []string{}
+	for i := range 9 {
+		// info keeps the 1st, 2nd, 5th and 8th, warn the 1st
+		if i == 0 || i == 1 || i == 4 || i == 7 {
+			expected = append(expected, "hot")
+		}
+
+		if i == 0 {
+			expected = append(expected, "hot")
+		}
+
+		expected = append(expected, fmt.Sprintf("unsampled %d", i))
+	}
+
+	expected = append(expected, "cold", "hot")
+	assert.Equal(t, expected, hook.getMessages())
+
+	require.NoError(t, h.Close())
+	require.NoError(t, h.Close(), "Expected closing twice to be fine")
+
+	sampledOut := []any{}
+
+	for i, msg := range hook.getMessages() {
+		if msg == samplingSummaryMessage {
+			sampledOut = append(sampledOut, hook.data[i]["sampled_out"])
+		}
+	}
+
+	assert.ElementsMatch(t, []any{uint64(5), uint64(8)}, sampledOut, "Expected a summary for info and warn on close")
+}
+
+func TestSamplingHookReports(t *testing.T) {
+	previous := samplingReportInterval
+	samplingReportInterval = 10 * time.Millisecond
+
+	t.Cleanup(func() { samplingReportInterval = previous })
+
+	rules, err := getSamplingRules([]string{"info=1/0/1h"})
+	require.NoError(t, err)
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	h := newSamplingHook(logrus.New(), rules, hook)
+	t.Cleanup(func() { _ = h.Close() })
+
+	for range 3 {
+		require.NoError(t, h.Fire(&logrus.Entry{Level: logrus.InfoLevel, Message: "hot"}))
+	}
+
+	assert.Eventually(t, func() bool {
+		messages := hook.getMessages()
+
+		return len(messages) == 2 && messages[1] == samplingSummaryMessage
+	}, 5*time.Second, 10*time.Millisecond, "Expected a periodic summary")
+
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+
+	assert.Equal(t, logrus.Fields{"sampled_out": uint64(2)}, hook.data[1])
+}
+
+func TestConfigureSampling(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv(configKeyLogSampling, "info=1/0/1h")
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	logger, err := NewLogger(WithHooks(hook))
+	require.NoError(t, err)
+
+	require.Len(t, getConfiguredHooks(logger)[logrus.InfoLevel], 1)
+
+	sampling, ok := getConfiguredHooks(logger)[logrus.InfoLevel][0].(*samplingHook)
+	require.True(t, ok, "Expected the sampling hook in front of the outputs")
+
+	logger.Info("hot")
+	logger.Info("hot")
+	logger.Warn("hot")
+	assert.Equal(t, []string{"hot", "hot"}, hook.getMessages())
+
+	// reconfiguring reports what was sampled out and stops the reports
+	require.NoError(t, ConfigureLogger(logger, WithHooks(hook), WithSampling()))
+	assert.Equal(t, []string{"hot", "hot", samplingSummaryMessage}, hook.getMessages())
+
+	select {
+	case <-sampling.done:
+	default:
+		t.Fatal("Expected the previous sampling hook to be closed")
+	}
+
+	_, err = NewLogger(WithSampling("info=1/1"))
+	require.EqualError(t, err, "failed to set log sampling: info=1/1: invalid log sampling rule")
+}
@@ -0,0 +1,54 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// exitShutdownTimeout bounds how long logrus.Fatal waits for
+// the outputs to be flushed before exiting
+const exitShutdownTimeout = 5 * time.Second
+
+// Shutdown drains the async queues and closes the outputs opened for
+// all of the configured loggers, giving up once the context is done.
+// It's meant to run right before exiting, logging afterwards may fail.
+func Shutdown(ctx context.Context) error {
+	loggerOutputsMu.Lock()
+	outputs := loggerOutputs
+	loggerOutputs = map[*logrus.Logger][]io.Closer{}
+	loggerOutputsMu.Unlock()
+
+	done := make(chan error, 1)
+
+	go func() {
+		var firstErr error
+
+		for _, closers := range outputs {
+			if err := closeOutputs(closers); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+
+		done <- firstErr
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "failed to shut down log outputs")
+	}
+}
+
+// shutdownOnExit is registered as a logrus exit handler
+// so the entries logged with Fatal don't get lost
+func shutdownOnExit() {
+	ctx, cancel := context.WithTimeout(context.Background(), exitShutdownTimeout)
+	defer cancel()
+
+	_ = Shutdown(ctx)
+}
@@ -0,0 +1,80 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingCloser doesn't return from Close until it's released
+type blockingCloser struct {
+	release chan struct{}
+}
+
+func (c blockingCloser) Close() error {
+	<-c.release
+
+	return nil
+}
+
+func TestShutdown(t *testing.T) {
+	unsetEnvs(t)
+
+	logPath := filepath.Join(t.TempDir(), "app.log")
+
+	logger, err := NewLogger(
+		WithFormat("json"),
+		WithOutputs("file://"+logPath),
+		WithAsync(AsyncConfig{Enabled: true}),
+	)
+	require.NoError(t, err)
+
+	logger.Error("last words")
+
+	require.NoError(t, Shutdown(context.Background()))
+
+	content, err := os.ReadFile(logPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), `"msg":"last words"`)
+
+	loggerOutputsMu.Lock()
+	_, ok := loggerOutputs[logger]
+	loggerOutputsMu.Unlock()
+	assert.False(t, ok, "Expected the outputs to be forgotten")
+
+	require.NoError(t, Shutdown(context.Background()), "Expected shutting down twice to be fine")
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	closer := blockingCloser{release: make(chan struct{})}
+	defer close(closer.release)
+
+	require.NoError(t, setLoggerOutputs(logrus.New(), []io.Closer{closer}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	require.ErrorIs(t, Shutdown(ctx), context.DeadlineExceeded)
+}
+
+func TestAsyncHookWaitsForPanic(t *testing.T) {
+	unsetEnvs(t)
+
+	hook := newGatedHook()
+	close(hook.gate)
+
+	logger, err := NewLogger(WithHooks(hook), WithAsync(AsyncConfig{Enabled: true}))
+	require.NoError(t, err)
+
+	assert.Panics(t, func() { logger.Panic("going down") })
+	assert.Equal(t, []string{"going down"}, hook.getMessages(), "Expected the panic entry to be written")
+
+	require.NoError(t, Shutdown(context.Background()))
+}
@@ -0,0 +1,168 @@
+package logrusconfigurator
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+type signalAction int
+
+const (
+	// signalReload re-reads the config and reconfigures the logger
+	signalReload signalAction = iota
+	// signalLevelUp makes the logger one level more verbose
+	signalLevelUp
+	// signalLevelDown makes the logger one level less verbose
+	signalLevelDown
+)
+
+// HandleSignals makes the standard logger react to signals: SIGHUP
+// reconfigures it from the environment keeping its env prefix, config
+// file and hooks, with the given options applied on top, and SIGUSR1
+// and SIGUSR2 make it one level more or less verbose. The returned
+// function stops handling the signals. Nothing is handled outside of
+// unix, where these signals don't exist.
+func HandleSignals(opts ...Option) func() {
+	return HandleLoggerSignals(logrus.StandardLogger(), opts...)
+}
+
+// HandleLoggerSignals does what HandleSignals does but for the given logger
+func HandleLoggerSignals(logger *logrus.Logger, opts ...Option) func() {
+	actions := getSignalActions()
+	if len(actions) == 0 {
+		return func() {}
+	}
+
+	signals := make([]os.Signal, 0, len(actions))
+	for sig := range actions {
+		signals = append(signals, sig)
+	}
+
+	ch := make(chan os.Signal, 1)
+	done := make(chan struct{})
+
+	signal.Notify(ch, signals...)
+
+	go func() {
+		for {
+			select {
+			case sig := <-ch:
+				handleSignal(logger, actions[sig], opts...)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			signal.Stop(ch)
+			close(done)
+		})
+	}
+}
+
+func handleSignal(logger *logrus.Logger, action signalAction, opts ...Option) {
+	switch action {
+	case signalReload:
+		if err := reloadLogger(logger, opts...); err != nil {
+			logger.WithError(err).Error("logrus-configurator: failed to reload log config, keeping the old one")
+		}
+	case signalLevelUp:
+		stepLoggerLevel(logger, 1)
+	case signalLevelDown:
+		stepLoggerLevel(logger, -1)
+	}
+}
+
+// reloadLogger reconfigures the logger and reports what changed. What
+// only the code could have set, the env prefix, the config file and the
+// hooks, is kept from the last config with the given options on top.
+func reloadLogger(logger *logrus.Logger, opts ...Option) error {
+	previous, ok := getLoggerConfig(logger)
+	if ok {
+		opts = append([]Option{func(c *Config) {
+			c.EnvPrefix = previous.EnvPrefix
+			c.ConfigFile = previous.ConfigFile
+			c.Hooks = previous.Hooks
+			c.FieldHooks = previous.FieldHooks
+		}}, opts...)
+	}
+
+	if err := configure(logger, opts...); err != nil {
+		return err
+	}
+
+	current, _ := getLoggerConfig(logger)
+	current.internal().logChanges(logger, previous.internal())
+
+	return nil
+}
+
+// stepLoggerLevel moves the level by the given number of steps,
+// positive being more verbose, without going past trace or panic
+func stepLoggerLevel(logger *logrus.Logger, steps int) {
+	reconfigureMu.Lock()
+	defer reconfigureMu.Unlock()
+
+	previous := getLoggerBaseLevel(logger)
+	next := min(max(int(previous)+steps, int(logrus.PanicLevel)), int(logrus.TraceLevel))
+	logrusLevel := logrus.Level(next) //nolint:gosec
+
+	setLoggerBaseLevel(logger, logrusLevel)
+
+	if cfg, ok := getLoggerConfig(logger); ok {
+		cfg.Level = logrusLevel.String()
+		setLoggerConfig(logger, cfg)
+	}
+
+	logger.Infof("logrus-configurator: log level changed from %s to %s", previous, logrusLevel)
+}
+
+// logChanges logs the values that differ from the previous config
+// next to the debug line config.log writes
+func (c config) logChanges(logger *logrus.Logger, previous config) {
+	changes := c.changes(previous)
+	if len(changes) == 0 {
+		logger.Debug("logrus-configurator: config reloaded, nothing changed")
+
+		return
+	}
+
+	logger.Debugf("logrus-configurator: config reloaded, %s", strings.Join(changes, ", "))
+}
+
+// changes lists the fields that differ from the previous
+// config as "ENV_KEY: old -> new", with the secret ones masked
+func (c config) changes(previous config) []string {
+	current := reflect.ValueOf(c)
+	old := reflect.ValueOf(previous)
+	configType := current.Type()
+
+	changes := []string{}
+
+	for i := range configType.NumField() {
+		if reflect.DeepEqual(current.Field(i).Interface(), old.Field(i).Interface()) {
+			continue
+		}
+
+		field := configType.Field(i)
+		if field.Tag.Get("secret") == "true" {
+			changes = append(changes, field.Tag.Get("env")+": "+redactedPlaceholder+" -> "+redactedPlaceholder)
+
+			continue
+		}
+
+		changes = append(changes, fmt.Sprintf("%s: %v -> %v", field.Tag.Get("env"), old.Field(i), current.Field(i)))
+	}
+
+	return changes
+}
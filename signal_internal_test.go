@@ -0,0 +1,119 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigChanges(t *testing.T) {
+	previous := defaultConfig()
+
+	current := defaultConfig()
+	current.Level = levelDebug
+	current.Outputs = []string{outputStdout}
+	current.RedactHashKey = "s3cr3t"
+
+	assert.Equal(t, []string{
+		"LOG_LEVEL: info -> debug",
+		"LOG_OUTPUT: [] -> [stdout]",
+		"LOG_REDACT_HASH_KEY: [REDACTED] -> [REDACTED]",
+	}, current.changes(previous))
+	assert.Empty(t, previous.changes(previous))
+}
+
+func TestReloadLogger(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithLevel("debug"))
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+
+	opts := []Option{WithHooks(getAllLevelsHook(&buf)), WithFormat("json")}
+
+	t.Setenv(configKeyLogLevel, "trace")
+	require.NoError(t, reloadLogger(logger, opts...))
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+	assert.Contains(t, buf.String(), "LOG_LEVEL: debug -\\u003e trace")
+	assert.Contains(t, buf.String(), "LOG_FORMAT: text -\\u003e json")
+
+	t.Setenv(configKeyLogRedactHashKey, "super-secret-key")
+	require.NoError(t, reloadLogger(logger, opts...))
+	assert.Contains(t, buf.String(), "LOG_REDACT_HASH_KEY: [REDACTED] -\\u003e [REDACTED]")
+	assert.NotContains(t, buf.String(), "super-secret-key")
+
+	t.Setenv(configKeyLogLevel, "loud")
+	require.Error(t, reloadLogger(logger, opts...))
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+
+	cfg, ok := getLoggerConfig(logger)
+	require.True(t, ok)
+	assert.Equal(t, "trace", cfg.Level)
+}
+
+func TestReloadLoggerKeepsPrefixAndHooks(t *testing.T) {
+	unsetEnvs(t)
+	t.Setenv("AUDIT_"+configKeyLogLevel, "error")
+
+	var buf bytes.Buffer
+
+	hook := getAllLevelsHook(&buf)
+
+	logger, err := NewLogger(WithEnvPrefix("AUDIT"), WithHooks(hook))
+	require.NoError(t, err)
+	require.Equal(t, logrus.ErrorLevel, logger.GetLevel())
+
+	t.Setenv(configKeyLogLevel, "debug")
+	t.Setenv("AUDIT_"+configKeyLogLevel, "warn")
+	require.NoError(t, reloadLogger(logger))
+	assert.Equal(t, logrus.WarnLevel, logger.GetLevel(), "Expected the prefixed env vars to be read")
+
+	for _, lvl := range logrus.AllLevels {
+		require.Len(t, getConfiguredHooks(logger)[lvl], 1, "Expected the custom hooks to be kept for %v", lvl)
+
+		bound, ok := getConfiguredHooks(logger)[lvl][0].(*outputHook)
+		require.True(t, ok, "Expected the custom hook to be bound to the output format")
+		assert.Same(t, hook, bound.Hook)
+	}
+
+	cfg, ok := getLoggerConfig(logger)
+	require.True(t, ok)
+	assert.Equal(t, "AUDIT", cfg.EnvPrefix)
+
+	// the options still go on top
+	require.NoError(t, reloadLogger(logger, WithLevel("trace")))
+	assert.Equal(t, logrus.TraceLevel, logger.GetLevel())
+}
+
+func TestStepLoggerLevel(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithLevel("info"), WithHooks())
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name     string
+		steps    int
+		expected logrus.Level
+	}{
+		{name: "More verbose", steps: 1, expected: logrus.DebugLevel},
+		{name: "Capped at trace", steps: 5, expected: logrus.TraceLevel},
+		{name: "Less verbose", steps: -2, expected: logrus.InfoLevel},
+		{name: "Capped at panic", steps: -10, expected: logrus.PanicLevel},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			stepLoggerLevel(logger, tc.steps)
+			assert.Equal(t, tc.expected, logger.GetLevel())
+
+			cfg, ok := getLoggerConfig(logger)
+			require.True(t, ok)
+			assert.Equal(t, tc.expected.String(), cfg.Level)
+		})
+	}
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package logrusconfigurator
+
+import "os"
+
+func getSignalActions() map[os.Signal]signalAction {
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package logrusconfigurator
+
+import (
+	"os"
+	"syscall"
+)
+
+func getSignalActions() map[os.Signal]signalAction {
+	return map[os.Signal]signalAction{
+		syscall.SIGHUP:  signalReload,
+		syscall.SIGUSR1: signalLevelUp,
+		syscall.SIGUSR2: signalLevelDown,
+	}
+}
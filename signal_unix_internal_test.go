@@ -0,0 +1,43 @@
+//go:build unix
+
+package logrusconfigurator
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleLoggerSignals(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithHooks())
+	require.NoError(t, err)
+
+	stop := HandleLoggerSignals(logger, WithHooks())
+	defer stop()
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR1))
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.DebugLevel
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGUSR2))
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.InfoLevel
+	}, time.Second, 10*time.Millisecond)
+
+	t.Setenv(configKeyLogLevel, "error")
+	require.NoError(t, syscall.Kill(os.Getpid(), syscall.SIGHUP))
+	assert.Eventually(t, func() bool {
+		return logger.GetLevel() == logrus.ErrorLevel
+	}, time.Second, 10*time.Millisecond)
+
+	stop()
+	stop()
+}
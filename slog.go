@@ -0,0 +1,174 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+// slogErrorKey is the attr key that becomes the logrus error field
+const slogErrorKey = "err"
+
+// slogDefault is the logger installed as the slog default along
+// with what slog was set to before so it can be put back. They're
+// guarded by stdlibLogMu as slog takes over the log package too.
+//
+//nolint:gochecknoglobals
+var (
+	slogDefault         *logrus.Logger
+	slogDefaultPrevious *slog.Logger
+)
+
+// SlogHandler is a slog.Handler that passes the records on to a logrus
+// logger so code using log/slog shares its config. The levels map to
+// the closest logrus ones, the attrs become fields with the group names
+// joined by dots and the caller is the one the record was logged from.
+type SlogHandler struct {
+	logger *logrus.Logger
+	fields logrus.Fields
+	prefix string
+}
+
+// NewSlogHandler returns a SlogHandler for the
+// given logger or for the standard one when it's nil
+func NewSlogHandler(logger *logrus.Logger) *SlogHandler {
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	addContextHook(logger)
+
+	return &SlogHandler{logger: logger, fields: logrus.Fields{}}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, lvl slog.Level) bool {
+	return h.logger.IsLevelEnabled(getSlogLogrusLevel(lvl))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	fields := make(logrus.Fields, len(h.fields)+r.NumAttrs())
+	maps.Copy(fields, h.fields)
+
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(fields, h.prefix, a)
+
+		return true
+	})
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	if r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		ctx = withCaller(ctx, frame)
+	}
+
+	entry := h.logger.WithContext(ctx).WithFields(fields)
+	if !r.Time.IsZero() {
+		entry = entry.WithTime(r.Time)
+	}
+
+	entry.Log(getSlogLogrusLevel(r.Level), r.Message)
+
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := maps.Clone(h.fields)
+	for _, a := range attrs {
+		addSlogAttr(fields, h.prefix, a)
+	}
+
+	return &SlogHandler{logger: h.logger, fields: fields, prefix: h.prefix}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	return &SlogHandler{logger: h.logger, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// addSlogAttr flattens the attr into the fields. Groups add
+// their name to the keys of their attrs unless it's empty.
+func addSlogAttr(fields logrus.Fields, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		groupPrefix := prefix
+		if a.Key != "" {
+			groupPrefix += a.Key + "."
+		}
+
+		for _, groupAttr := range a.Value.Group() {
+			addSlogAttr(fields, groupPrefix, groupAttr)
+		}
+
+		return
+	}
+
+	key := prefix + a.Key
+	value := a.Value.Any()
+
+	if _, ok := value.(error); ok && key == slogErrorKey {
+		key = logrus.ErrorKey
+	}
+
+	fields[key] = value
+}
+
+func getSlogLogrusLevel(lvl slog.Level) logrus.Level {
+	switch {
+	case lvl < slog.LevelDebug:
+		return logrus.TraceLevel
+	case lvl < slog.LevelInfo:
+		return logrus.DebugLevel
+	case lvl < slog.LevelWarn:
+		return logrus.InfoLevel
+	case lvl < slog.LevelError:
+		return logrus.WarnLevel
+	default:
+		return logrus.ErrorLevel
+	}
+}
+
+// setSlogDefault installs a SlogHandler for the logger as the slog
+// default or, when disabled, puts back what was there before if the
+// logger is the one installed
+func setSlogDefault(logger *logrus.Logger, enabled bool) {
+	stdlibLogMu.Lock()
+	defer stdlibLogMu.Unlock()
+
+	if enabled {
+		saveStdlibLog()
+
+		if slogDefault == nil {
+			slogDefaultPrevious = slog.Default()
+		}
+
+		slogDefault = logger
+		slog.SetDefault(slog.New(NewSlogHandler(logger)))
+		updateStdlibLog()
+
+		return
+	}
+
+	if slogDefault != logger {
+		return
+	}
+
+	slog.SetDefault(slogDefaultPrevious)
+
+	slogDefault = nil
+	slogDefaultPrevious = nil
+
+	updateStdlibLog()
+}
@@ -0,0 +1,163 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSlogLogrusLevel(t *testing.T) {
+	testCases := map[slog.Level]logrus.Level{
+		slog.LevelDebug - 4: logrus.TraceLevel,
+		slog.LevelDebug:     logrus.DebugLevel,
+		slog.LevelInfo - 1:  logrus.DebugLevel,
+		slog.LevelInfo:      logrus.InfoLevel,
+		slog.LevelWarn:      logrus.WarnLevel,
+		slog.LevelError - 1: logrus.WarnLevel,
+		slog.LevelError:     logrus.ErrorLevel,
+		slog.LevelError + 4: logrus.ErrorLevel,
+	}
+
+	for lvl, expected := range testCases {
+		assert.Equal(t, expected, getSlogLogrusLevel(lvl), lvl.String())
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	unsetEnvs(t)
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithLevel("debug"),
+		WithFormat("json"),
+		WithReportCaller(true),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+
+	slogger := slog.New(NewSlogHandler(logger)).With("app", "test").WithGroup("req")
+
+	testCases := []struct {
+		name     string
+		log      func()
+		expected map[string]any
+	}{
+		{
+			name: "Attrs and groups",
+			log: func() {
+				slogger.Info("handled", "id", 7, slog.Group("user", "name", "bob"), slog.Group("empty"))
+			},
+			expected: map[string]any{
+				"level":         "info",
+				"msg":           "handled",
+				"app":           "test",
+				"req.id":        float64(7),
+				"req.user.name": "bob",
+			},
+		},
+		{
+			name: "Error",
+			log: func() {
+				slog.New(NewSlogHandler(logger)).Error("failed", slogErrorKey, errors.New("boom"))
+			},
+			expected: map[string]any{
+				"level":         "error",
+				"msg":           "failed",
+				logrus.ErrorKey: "boom",
+			},
+		},
+		{
+			name: "Debug",
+			log: func() {
+				slogger.Debug("details")
+			},
+			expected: map[string]any{
+				"level": "debug",
+				"msg":   "details",
+				"app":   "test",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.log()
+
+			result := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+
+			// the caller is the test, not the handler
+			assert.Contains(t, result["file"], "slog_internal_test.go")
+			assert.Contains(t, result["func"], "TestSlogHandler")
+
+			delete(result, "time")
+			delete(result, "file")
+			delete(result, "func")
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	unsetEnvs(t)
+
+	logger, err := NewLogger(WithLevel("warn"), WithHooks())
+	require.NoError(t, err)
+
+	h := NewSlogHandler(logger)
+	assert.False(t, h.Enabled(t.Context(), slog.LevelInfo))
+	assert.True(t, h.Enabled(t.Context(), slog.LevelWarn))
+}
+
+func TestSlogHandlerUnconfiguredLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	logger := logrus.New()
+	logger.SetOutput(&buf)
+	logger.SetFormatter(&logrus.JSONFormatter{CallerPrettyfier: callerPrettyfier})
+	logger.SetReportCaller(true)
+
+	NewSlogHandler(logger)
+	slog.New(NewSlogHandler(logger)).Info("hello")
+
+	assert.Len(t, logger.Hooks[logrus.InfoLevel], 1, "Expected a single contextHook")
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "hello", result["msg"])
+	assert.Contains(t, result["file"], "slog_internal_test.go")
+}
+
+func TestWithSlog(t *testing.T) {
+	unsetEnvs(t)
+
+	previous := slog.Default()
+	logWriter := log.Writer()
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(WithSlog(true), WithFormat("json"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+	assert.IsType(t, &SlogHandler{}, slog.Default().Handler())
+
+	slog.Info("from slog")
+	assert.Contains(t, buf.String(), `"msg":"from slog"`)
+
+	// reconfiguring another logger leaves the default alone
+	_, err = NewLogger(WithHooks())
+	require.NoError(t, err)
+	assert.IsType(t, &SlogHandler{}, slog.Default().Handler())
+
+	require.NoError(t, ConfigureLogger(logger, WithSlog(false), WithHooks()))
+	assert.Same(t, previous, slog.Default())
+	assert.Equal(t, logWriter, log.Writer())
+}
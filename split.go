@@ -0,0 +1,74 @@
+package logrusconfigurator
+
+import (
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+type split string
+
+const (
+	splitOff     split = "off"
+	splitDefault split = "default"
+	splitCustom  split = "custom"
+)
+
+// defaultStderrLevels are the levels sent to stderr, everything else goes to stdout
+//
+//nolint:gochecknoglobals
+var defaultStderrLevels = []logrus.Level{
+	logrus.PanicLevel,
+	logrus.FatalLevel,
+	logrus.ErrorLevel,
+	logrus.WarnLevel,
+}
+
+// getStderrLevels returns the levels routed to stderr for the split.
+// Off sends everything to stdout. The custom levels only go with the
+// custom split, anything else would quietly ignore them.
+func getStderrLevels(s split, customLevels []string) ([]logrus.Level, error) {
+	if s == "" {
+		s = splitDefault
+	}
+
+	if len(customLevels) > 0 && s != splitCustom {
+		return nil, errors.Wrapf(errInvalidLogSplit, "stderr levels need the custom split, not %s", s)
+	}
+
+	switch s {
+	case splitOff:
+		return []logrus.Level{}, nil
+	case splitDefault:
+		return defaultStderrLevels, nil
+	case splitCustom:
+		levels := make([]logrus.Level, 0, len(customLevels))
+
+		for _, lvl := range customLevels {
+			logrusLevel, err := getLogrusLevel(level(lvl))
+			if err != nil {
+				return nil, err
+			}
+
+			levels = append(levels, logrusLevel)
+		}
+
+		return levels, nil
+	default:
+		return nil, errors.Wrap(errInvalidLogSplit, string(s))
+	}
+}
+
+// getStdoutLevels returns all of the levels not routed to stderr
+func getStdoutLevels(stderrLevels []logrus.Level) []logrus.Level {
+	levels := []logrus.Level{}
+
+	for _, lvl := range logrus.AllLevels {
+		if !slices.Contains(stderrLevels, lvl) {
+			levels = append(levels, lvl)
+		}
+	}
+
+	return levels
+}
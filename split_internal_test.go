@@ -0,0 +1,230 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetStderrLevels(t *testing.T) {
+	testCases := []struct {
+		name         string
+		split        split
+		customLevels []string
+		expected     []logrus.Level
+		expectError  bool
+	}{
+		{
+			name:     "Default",
+			split:    splitDefault,
+			expected: defaultStderrLevels,
+		},
+		{
+			name:     "Empty means default",
+			split:    "",
+			expected: defaultStderrLevels,
+		},
+		{
+			name:         "Off with custom levels",
+			split:        splitOff,
+			customLevels: []string{"error"},
+			expectError:  true,
+		},
+		{
+			name:         "Default with custom levels",
+			split:        "",
+			customLevels: []string{"error"},
+			expectError:  true,
+		},
+		{
+			name:         "Custom",
+			split:        splitCustom,
+			customLevels: []string{"error", "FATAL", "panic"},
+			expected:     []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel},
+		},
+		{
+			name:     "Custom without levels",
+			split:    splitCustom,
+			expected: []logrus.Level{},
+		},
+		{
+			name:         "Custom with invalid level",
+			split:        splitCustom,
+			customLevels: []string{"error", "nope"},
+			expectError:  true,
+		},
+		{
+			name:        "Invalid split",
+			split:       "sideways",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			levels, err := getStderrLevels(tc.split, tc.customLevels)
+			if tc.expectError {
+				require.Error(t, err)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, levels)
+		})
+	}
+}
+
+func TestGetStdoutLevels(t *testing.T) {
+	assert.Equal(t,
+		[]logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel},
+		getStdoutLevels(defaultStderrLevels),
+	)
+	assert.Equal(t, logrus.AllLevels, getStdoutLevels(nil))
+	assert.Equal(t,
+		[]logrus.Level{logrus.WarnLevel, logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel},
+		getStdoutLevels([]logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}),
+	)
+}
+
+func TestConfigureWithSplit(t *testing.T) {
+	unsetEnvs(t)
+
+	testCases := []struct {
+		name              string
+		split             string
+		stderrLevels      string
+		outputs           string
+		expectedOnStderr  []logrus.Level
+		expectedOnStdout  []logrus.Level
+		expectError       bool
+		expectedErrString string
+	}{
+		{
+			name:             "Default split",
+			split:            "default",
+			expectedOnStderr: defaultStderrLevels,
+			expectedOnStdout: []logrus.Level{logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel},
+		},
+		{
+			name:             "Split off",
+			split:            "off",
+			expectedOnStdout: logrus.AllLevels,
+		},
+		{
+			name:             "Custom split",
+			split:            "custom",
+			stderrLevels:     "error,fatal,panic",
+			expectedOnStderr: []logrus.Level{logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel},
+			expectedOnStdout: []logrus.Level{logrus.WarnLevel, logrus.InfoLevel, logrus.DebugLevel, logrus.TraceLevel},
+		},
+		{
+			name:              "Custom split with invalid level",
+			split:             "custom",
+			stderrLevels:      "error,nope",
+			expectError:       true,
+			expectedErrString: "failed to set log outputs",
+		},
+		{
+			name:              "Invalid split",
+			split:             "nope",
+			expectError:       true,
+			expectedErrString: "invalid log split",
+		},
+		{
+			name:              "Stderr levels without the custom split",
+			split:             "default",
+			stderrLevels:      "error",
+			expectError:       true,
+			expectedErrString: "stderr levels need the custom split, not default",
+		},
+		{
+			name:              "Split off with stderr only",
+			split:             "off",
+			outputs:           "stderr",
+			expectError:       true,
+			expectedErrString: "stderr gets no levels with the split",
+		},
+		{
+			name:              "Custom split with stdout only and every level on stderr",
+			split:             "custom",
+			stderrLevels:      "trace,debug,info,warn,error,fatal,panic",
+			outputs:           "stdout",
+			expectError:       true,
+			expectedErrString: "stdout gets no levels with the split",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(configKeyLogSplit, tc.split)
+			t.Setenv(configKeyLogStderrLevels, tc.stderrLevels)
+
+			if tc.outputs != "" {
+				t.Setenv(configKeyLogOutput, tc.outputs)
+			}
+
+			logger, err := NewLogger()
+			if tc.expectError {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedErrString)
+
+				return
+			}
+
+			require.NoError(t, err)
+
+			for _, lvl := range logrus.AllLevels {
+				hooks := getLevelOutputHooks(logger, lvl)
+				require.Len(t, hooks, 1, "Expected exactly one console hook for level %v", lvl)
+
+				writerHook, ok := hooks[0].(*outputHook)
+				require.True(t, ok, "Hook should be of type outputHook")
+
+				expectedWriter := os.Stdout
+				if slices.Contains(tc.expectedOnStderr, lvl) {
+					expectedWriter = os.Stderr
+				}
+
+				assert.Equal(t, expectedWriter, writerHook.Writer, "Writer mismatch for level %v", lvl)
+			}
+
+			assert.Equal(t, tc.expectedOnStdout, getStdoutLevels(tc.expectedOnStderr))
+		})
+	}
+}
+
+func TestWithStderrLevels(t *testing.T) {
+	unsetEnvs(t)
+
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+
+	stderrLevels, err := getStderrLevels(splitCustom, []string{"error"})
+	require.NoError(t, err)
+
+	logger, err := NewLogger(
+		WithStderrLevels("error"),
+		WithLevel("debug"),
+	)
+	require.NoError(t, err)
+
+	// swap the console writers for buffers to see where each level lands
+	setLoggerHooks(logger, getStdoutLevelsHook(stdout, stderrLevels), getStderrLevelsHook(stderr, stderrLevels))
+
+	logger.Warn("warn goes to stdout")
+	logger.Error("error goes to stderr")
+
+	assert.Contains(t, stdout.String(), "warn goes to stdout")
+	assert.NotContains(t, stdout.String(), "error goes to stderr")
+	assert.Contains(t, stderr.String(), "error goes to stderr")
+	assert.NotContains(t, stderr.String(), "warn goes to stdout")
+
+	assert.Len(t, logger.Hooks[logrus.WarnLevel], 1)
+	assert.Len(t, logger.Hooks[logrus.ErrorLevel], 1)
+}
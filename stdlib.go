@@ -0,0 +1,122 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+const stdlibLogPackage = "log"
+
+// the log package settings from before this package took it over
+// and the writer capturing its output, if it's captured
+//
+//nolint:gochecknoglobals
+var (
+	stdlibLogMu     sync.Mutex
+	stdlibLogSaved  bool
+	stdlibLogWriter io.Writer
+	stdlibLogFlags  int
+	stdlibLogPrefix string
+	stdlibCapture   *stdlibWriter
+)
+
+// stdlibWriter is the output of the log package when it's captured.
+// Every write is one log call which gets logged at the level with the
+// caller of the log function and without the log prefix and flags.
+type stdlibWriter struct {
+	logger *logrus.Logger
+	level  logrus.Level
+}
+
+func (w *stdlibWriter) Write(p []byte) (int, error) {
+	if !w.logger.IsLevelEnabled(w.level) {
+		return len(p), nil
+	}
+
+	ctx := context.Background()
+	if frame := getCallerAfter(stdlibLogPackage); frame.PC != 0 {
+		ctx = withCaller(ctx, frame)
+	}
+
+	w.logger.WithContext(ctx).Log(w.level, strings.TrimSuffix(string(p), "\n"))
+
+	return len(p), nil
+}
+
+// getStdlibLevel parses the level the log package output gets logged
+// at. Fatal and panic are out, a library's log.Print would end the
+// process.
+func getStdlibLevel(lvl level) (logrus.Level, error) {
+	logrusLevel, err := getLogrusLevel(lvl)
+	if err != nil {
+		return 0, err
+	}
+
+	if logrusLevel < logrus.ErrorLevel {
+		return 0, errors.Wrap(errInvalidLogLevel, string(lvl))
+	}
+
+	return logrusLevel, nil
+}
+
+// setStdlibCapture sends the log package output to the logger at the
+// level or, when disabled, stops doing so if it was this logger's
+func setStdlibCapture(logger *logrus.Logger, enabled bool, lvl logrus.Level) {
+	stdlibLogMu.Lock()
+	defer stdlibLogMu.Unlock()
+
+	switch {
+	case enabled:
+		saveStdlibLog()
+
+		stdlibCapture = &stdlibWriter{logger: logger, level: lvl}
+	case stdlibCapture != nil && stdlibCapture.logger == logger:
+		stdlibCapture = nil
+	default:
+		return
+	}
+
+	updateStdlibLog()
+}
+
+// saveStdlibLog remembers the log package settings the
+// first time this package is about to change them
+func saveStdlibLog() {
+	if stdlibLogSaved {
+		return
+	}
+
+	stdlibLogSaved = true
+	stdlibLogWriter = log.Writer()
+	stdlibLogFlags = log.Flags()
+	stdlibLogPrefix = log.Prefix()
+}
+
+// updateStdlibLog points the log package at the capturing writer, at
+// the slog default or back where it was, in that order of preference
+func updateStdlibLog() {
+	switch {
+	case stdlibCapture != nil:
+		log.SetOutput(stdlibCapture)
+		log.SetFlags(0)
+		log.SetPrefix("")
+	case slogDefault != nil:
+		// setting the default again makes slog take over the log package
+		log.SetPrefix(stdlibLogPrefix)
+		slog.SetDefault(slog.Default())
+	case stdlibLogSaved:
+		log.SetOutput(stdlibLogWriter)
+		log.SetFlags(stdlibLogFlags)
+		log.SetPrefix(stdlibLogPrefix)
+
+		stdlibLogSaved = false
+		stdlibLogWriter = nil
+	}
+}
@@ -0,0 +1,95 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"encoding/json"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureStdlib(t *testing.T) {
+	unsetEnvs(t)
+
+	logWriter, logFlags := log.Writer(), log.Flags()
+
+	log.SetPrefix("lib: ")
+	t.Cleanup(func() { log.SetPrefix("") })
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithCaptureStdlib("warn"),
+		WithFormat("json"),
+		WithReportCaller(true),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+
+	log.Printf("connection %d lost\n", 3)
+
+	result := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "warning", result["level"])
+	assert.Equal(t, "connection 3 lost", result["msg"])
+	assert.Contains(t, result["file"], "stdlib_internal_test.go")
+	assert.Contains(t, result["func"], "TestCaptureStdlib")
+
+	require.NoError(t, ConfigureLogger(logger, WithLevel("error"), WithCaptureStdlib("info"), WithHooks(getAllLevelsHook(&buf))))
+
+	buf.Reset()
+	log.Print("below the level")
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, ConfigureLogger(logger, WithHooks()))
+	assert.Equal(t, logWriter, log.Writer())
+	assert.Equal(t, logFlags, log.Flags())
+	assert.Equal(t, "lib: ", log.Prefix())
+}
+
+func TestCaptureStdlibInvalidLevel(t *testing.T) {
+	unsetEnvs(t)
+
+	t.Setenv(configKeyLogCaptureStdlib, "true")
+	t.Setenv(configKeyLogCaptureStdlibLevel, "loud")
+
+	_, err := NewLogger()
+	require.EqualError(t, err, "failed to set stdlib log capture level: loud: invalid log level")
+
+	for _, lvl := range []string{"fatal", "panic"} {
+		t.Setenv(configKeyLogCaptureStdlibLevel, lvl)
+
+		_, err = NewLogger()
+		require.EqualError(t, err, "failed to set stdlib log capture level: "+lvl+": invalid log level")
+
+		_, err = NewLogger(WithCaptureStdlib(lvl))
+		require.ErrorIs(t, err, errInvalidLogLevel)
+	}
+}
+
+func TestCaptureStdlibWithSlog(t *testing.T) {
+	unsetEnvs(t)
+
+	logWriter := log.Writer()
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(WithSlog(true), WithCaptureStdlib("error"), WithHooks(getAllLevelsHook(&buf)))
+	require.NoError(t, err)
+
+	log.Print("captured")
+	assert.Contains(t, buf.String(), "level=error")
+
+	// without the capture slog takes the log package over again
+	require.NoError(t, ConfigureLogger(logger, WithSlog(true), WithHooks(getAllLevelsHook(&buf))))
+
+	buf.Reset()
+	log.Print("through slog")
+	assert.Contains(t, buf.String(), "level=info")
+	assert.Contains(t, buf.String(), "through slog")
+
+	require.NoError(t, ConfigureLogger(logger, WithHooks()))
+	assert.Equal(t, logWriter, log.Writer())
+}
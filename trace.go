@@ -0,0 +1,67 @@
+package logrusconfigurator
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	traceIDField    = "trace_id"
+	spanIDField     = "span_id"
+	traceFlagsField = "trace_flags"
+)
+
+// TraceContext is the W3C trace context of a span
+type TraceContext struct {
+	TraceID [16]byte
+	SpanID  [8]byte
+	Flags   byte
+}
+
+// IsValid tells whether both the trace and the span ids are set
+func (tc TraceContext) IsValid() bool {
+	return tc.TraceID != [16]byte{} && tc.SpanID != [8]byte{}
+}
+
+// TraceHook adds the trace_id, span_id and trace_flags fields of the
+// span in the context of the entry in W3C format. It doesn't depend on
+// a tracing library, SpanContext bridges to the one in use. The
+// logrusotel module has the one for OpenTelemetry.
+//
+// It goes in front of the output hooks with WithFieldHooks.
+type TraceHook struct {
+	// SpanContext returns the trace context of the span in the
+	// context and whether there's a span at all
+	SpanContext func(ctx context.Context) (TraceContext, bool)
+	// RecordError, when set, gets the entries at error level and above
+	// that have a span so they can be recorded as span events
+	RecordError func(ctx context.Context, entry *logrus.Entry)
+}
+
+func (h *TraceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *TraceHook) Fire(entry *logrus.Entry) error {
+	if entry.Context == nil || h.SpanContext == nil {
+		return nil
+	}
+
+	tc, ok := h.SpanContext(entry.Context)
+	if !ok || !tc.IsValid() {
+		return nil
+	}
+
+	entry.Data[traceIDField] = hex.EncodeToString(tc.TraceID[:])
+	entry.Data[spanIDField] = hex.EncodeToString(tc.SpanID[:])
+	entry.Data[traceFlagsField] = fmt.Sprintf("%02x", tc.Flags)
+
+	if h.RecordError != nil && entry.Level <= logrus.ErrorLevel {
+		h.RecordError(entry.Context, entry)
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package logrusconfigurator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testSpanKey struct{}
+
+func testSpanContext(ctx context.Context) (TraceContext, bool) {
+	tc, ok := ctx.Value(testSpanKey{}).(TraceContext)
+
+	return tc, ok
+}
+
+func TestTraceHook(t *testing.T) {
+	unsetEnvs(t)
+
+	recorded := []string{}
+	hook := &TraceHook{
+		SpanContext: testSpanContext,
+		RecordError: func(_ context.Context, entry *logrus.Entry) {
+			recorded = append(recorded, entry.Message)
+		},
+	}
+
+	var buf bytes.Buffer
+
+	logger, err := NewLogger(
+		WithFormat("json"),
+		WithFieldHooks(hook),
+		WithHooks(getAllLevelsHook(&buf)),
+	)
+	require.NoError(t, err)
+
+	span := TraceContext{
+		TraceID: [16]byte{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:  [8]byte{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		Flags:   1,
+	}
+	ctx := context.WithValue(t.Context(), testSpanKey{}, span)
+
+	testCases := []struct {
+		name     string
+		log      func()
+		expected map[string]any
+	}{
+		{
+			name: "Span",
+			log:  func() { logger.WithContext(ctx).Info("traced") },
+			expected: map[string]any{
+				"level":         "info",
+				"msg":           "traced",
+				traceIDField:    "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanIDField:     "00f067aa0ba902b7",
+				traceFlagsField: "01",
+			},
+		},
+		{
+			name: "Error recorded on the span",
+			log:  func() { logger.WithContext(ctx).Error("failed") },
+			expected: map[string]any{
+				"level":         "error",
+				"msg":           "failed",
+				traceIDField:    "4bf92f3577b34da6a3ce929d0e0e4736",
+				spanIDField:     "00f067aa0ba902b7",
+				traceFlagsField: "01",
+			},
+		},
+		{
+			name:     "Invalid span",
+			log:      func() { logger.WithContext(context.WithValue(ctx, testSpanKey{}, TraceContext{})).Error("no span") },
+			expected: map[string]any{"level": "error", "msg": "no span"},
+		},
+		{
+			name:     "No context",
+			log:      func() { logger.Error("no context") },
+			expected: map[string]any{"level": "error", "msg": "no context"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			buf.Reset()
+			tc.log()
+
+			result := map[string]any{}
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+			delete(result, "time")
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+
+	assert.Equal(t, []string{"failed"}, recorded)
+}